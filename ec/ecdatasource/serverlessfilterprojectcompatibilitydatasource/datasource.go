@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessfilterprojectcompatibilitydatasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var _ datasource.DataSource = &DataSource{}
+var _ datasource.DataSourceWithConfigure = &DataSource{}
+
+type DataSource struct {
+	client serverless.ClientWithResponsesInterface
+}
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_filter_project_compatibility"
+}
+
+func (d *DataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.client = clients.Serverless
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Prevent panic if the provider has not been configured.
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filterResp, err := d.client.GetTrafficFilterWithResponse(ctx, model.TrafficFilterID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(util.APIReadFailed, util.OperationDetail("read traffic filter", util.RequestErrorDetail(err)))
+		return
+	}
+	if filterResp.JSON200 == nil {
+		resp.Diagnostics.AddError(
+			util.APIReadFailed,
+			util.OperationDetail("read traffic filter", util.APIFailureDetail(filterResp.HTTPResponse, filterResp.Body)),
+		)
+		return
+	}
+
+	projectRegion, diags := d.getProjectRegion(ctx, model.ProjectID.ValueString(), model.ProjectType.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	compatible, reason := checkCompatibility(filterResp.JSON200, model.ProjectID.ValueString(), projectRegion)
+	model.Compatible = types.BoolValue(compatible)
+	model.Reason = types.StringValue(reason)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// checkCompatibility reports whether the filter can be associated with a project in the given region.
+// Region IDs include the cloud provider, so this covers cloud provider mismatches as well.
+func checkCompatibility(filter *serverless.TrafficFilterInfo, projectID, projectRegion string) (bool, string) {
+	if filter.Region != projectRegion {
+		return false, fmt.Sprintf("traffic filter %s is in region %s, but project %s is in region %s", filter.Id, filter.Region, projectID, projectRegion)
+	}
+	return true, ""
+}
+
+// getProjectRegion retrieves the region of a project
+func (d *DataSource) getProjectRegion(ctx context.Context, projectID, projectType string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var region serverless.RegionID
+	var statusCode int
+	var httpResp *http.Response
+	var body []byte
+	var err error
+
+	switch projectType {
+	case "elasticsearch":
+		var resp *serverless.GetElasticsearchProjectResponse
+		if resp, err = d.client.GetElasticsearchProjectWithResponse(ctx, projectID); err == nil {
+			statusCode, httpResp, body = resp.StatusCode(), resp.HTTPResponse, resp.Body
+			if resp.JSON200 != nil {
+				region = resp.JSON200.RegionId
+			}
+		}
+	case "observability":
+		var resp *serverless.GetObservabilityProjectResponse
+		if resp, err = d.client.GetObservabilityProjectWithResponse(ctx, projectID); err == nil {
+			statusCode, httpResp, body = resp.StatusCode(), resp.HTTPResponse, resp.Body
+			if resp.JSON200 != nil {
+				region = resp.JSON200.RegionId
+			}
+		}
+	case "security":
+		var resp *serverless.GetSecurityProjectResponse
+		if resp, err = d.client.GetSecurityProjectWithResponse(ctx, projectID); err == nil {
+			statusCode, httpResp, body = resp.StatusCode(), resp.HTTPResponse, resp.Body
+			if resp.JSON200 != nil {
+				region = resp.JSON200.RegionId
+			}
+		}
+	default:
+		diags.AddError("Invalid project type", fmt.Sprintf("Unknown project type: %s", projectType))
+		return "", diags
+	}
+
+	if err != nil {
+		diags.AddError(util.APIReadFailed, util.OperationDetail("read project", util.RequestErrorDetail(err)))
+		return "", diags
+	}
+	if statusCode == http.StatusNotFound {
+		diags.AddError("Project not found", fmt.Sprintf("%s project %s not found", projectType, projectID))
+		return "", diags
+	}
+	if statusCode != http.StatusOK {
+		diags.AddError(
+			util.APIReadFailed,
+			util.OperationDetail("read project", util.APIFailureDetail(httpResp, body)),
+		)
+		return "", diags
+	}
+
+	return string(region), diags
+}
@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessfilterprojectcompatibilitydatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name               string
+		filterRegion       string
+		projectRegion      string
+		expectedCompatible bool
+		expectedReason     string
+	}{
+		{
+			name:               "compatible when the regions match",
+			filterRegion:       "aws-us-east-1",
+			projectRegion:      "aws-us-east-1",
+			expectedCompatible: true,
+			expectedReason:     "",
+		},
+		{
+			name:               "incompatible when the regions differ",
+			filterRegion:       "aws-us-east-1",
+			projectRegion:      "aws-eu-west-1",
+			expectedCompatible: false,
+			expectedReason:     "traffic filter filter-id is in region aws-us-east-1, but project project-id is in region aws-eu-west-1",
+		},
+		{
+			name:               "incompatible when the cloud providers differ",
+			filterRegion:       "aws-us-east-1",
+			projectRegion:      "gcp-us-east1",
+			expectedCompatible: false,
+			expectedReason:     "traffic filter filter-id is in region aws-us-east-1, but project project-id is in region gcp-us-east1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			d := NewDataSource().(*DataSource)
+			schemaResp := datasource.SchemaResponse{}
+			d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+			require.False(t, schemaResp.Diagnostics.HasError())
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().GetTrafficFilterWithResponse(ctx, "filter-id").Return(&serverless.GetTrafficFilterResponse{
+				JSON200:      &serverless.TrafficFilterInfo{Id: "filter-id", Region: tt.filterRegion},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+			mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+				JSON200:      &serverless.ObservabilityProject{Id: "project-id", RegionId: tt.projectRegion},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+			d.client = mockClient
+
+			config := modelV0{
+				TrafficFilterID: types.StringValue("filter-id"),
+				ProjectID:       types.StringValue("project-id"),
+				ProjectType:     types.StringValue("observability"),
+				Compatible:      types.BoolNull(),
+				Reason:          types.StringNull(),
+			}
+			req := datasource.ReadRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+				},
+			}
+			resp := datasource.ReadResponse{
+				State: tfsdk.State{Schema: schemaResp.Schema},
+			}
+			d.Read(ctx, req, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var state modelV0
+			require.False(t, resp.State.Get(ctx, &state).HasError())
+			require.Equal(t, tt.expectedCompatible, state.Compatible.ValueBool())
+			require.Equal(t, tt.expectedReason, state.Reason.ValueString())
+		})
+	}
+}
+
+func TestRead_ProjectNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(ctx, "project-id").Return(&serverless.GetSecurityProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+
+	d := &DataSource{client: mockClient}
+	_, diags := d.getProjectRegion(ctx, "project-id", "security")
+
+	require.True(t, diags.HasError())
+	require.Equal(t, "Project not found", diags.Errors()[0].Summary())
+}
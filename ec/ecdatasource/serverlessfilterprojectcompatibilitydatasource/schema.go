@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessfilterprojectcompatibilitydatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to check whether a serverless traffic filter can be associated with a serverless project, e.g. in a `precondition` of the association.",
+		Attributes: map[string]schema.Attribute{
+			"traffic_filter_id": schema.StringAttribute{
+				Description: "The ID of the traffic filter.",
+				Required:    true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "The ID of the project.",
+				Required:    true,
+			},
+			"project_type": schema.StringAttribute{
+				Description: "The type of the project. Must be one of: elasticsearch, observability, security.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("elasticsearch", "observability", "security"),
+				},
+			},
+
+			// computed fields
+			"compatible": schema.BoolAttribute{
+				Description: "Whether the traffic filter can be associated with the project.",
+				Computed:    true,
+			},
+			"reason": schema.StringAttribute{
+				Description: "Why the traffic filter can't be associated with the project. Empty if they are compatible.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type modelV0 struct {
+	TrafficFilterID types.String `tfsdk:"traffic_filter_id"`
+	ProjectID       types.String `tfsdk:"project_id"`
+	ProjectType     types.String `tfsdk:"project_type"`
+	Compatible      types.Bool   `tfsdk:"compatible"`
+	Reason          types.String `tfsdk:"reason"`
+}
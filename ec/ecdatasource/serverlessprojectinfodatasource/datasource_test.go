@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojectinfodatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func read(t *testing.T, client serverless.ClientWithResponsesInterface, projectType types.String) (modelV0, datasource.ReadResponse) {
+	ctx := context.Background()
+
+	d := &DataSource{client: client}
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	config := modelV0{
+		ProjectID:     types.StringValue("project-id"),
+		ProjectType:   projectType,
+		Name:          types.StringNull(),
+		Region:        types.StringNull(),
+		CloudProvider: types.StringNull(),
+	}
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	d.Read(ctx, req, &resp)
+
+	var state modelV0
+	if !resp.Diagnostics.HasError() {
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+	}
+	return state, resp
+}
+
+func TestRead_ExplicitProjectType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+		JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-project", RegionId: "gcp-us-central1"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	state, resp := read(t, mockClient, types.StringValue("security"))
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, modelV0{
+		ProjectID:     types.StringValue("project-id"),
+		ProjectType:   types.StringValue("security"),
+		Name:          types.StringValue("my-project"),
+		Region:        types.StringValue("gcp-us-central1"),
+		CloudProvider: types.StringValue("gcp"),
+	}, state)
+}
+
+func TestRead_DetectsProjectType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetObservabilityProjectResponse{
+		JSON200:      &serverless.ObservabilityProject{Id: "project-id", Name: "my-project", RegionId: "aws-us-east-1"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).Times(2)
+
+	state, resp := read(t, mockClient, types.StringNull())
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, modelV0{
+		ProjectID:     types.StringValue("project-id"),
+		ProjectType:   types.StringValue("observability"),
+		Name:          types.StringValue("my-project"),
+		Region:        types.StringValue("aws-us-east-1"),
+		CloudProvider: types.StringValue("aws"),
+	}, state)
+}
+
+func TestRead_ProjectNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetObservabilityProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+
+	_, resp := read(t, mockClient, types.StringNull())
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Project not found", resp.Diagnostics.Errors()[0].Summary())
+}
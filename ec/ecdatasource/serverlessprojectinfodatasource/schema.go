@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojectinfodatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to retrieve the region and type of a serverless project, e.g. to create a traffic filter in the same region.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Description: "The ID of the project.",
+				Required:    true,
+			},
+			"project_type": schema.StringAttribute{
+				Description: "The type of the project. Must be one of: elasticsearch, observability, security. Detected if not set, by looking the project up under each of the project types.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("elasticsearch", "observability", "security"),
+				},
+			},
+
+			// computed fields
+			"name": schema.StringAttribute{
+				Description: "The name of the project.",
+				Computed:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region of the project, e.g. aws-us-east-1.",
+				Computed:    true,
+			},
+			"cloud_provider": schema.StringAttribute{
+				Description: "The cloud provider hosting the project, derived from its region: aws, azure or gcp.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type modelV0 struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	ProjectType   types.String `tfsdk:"project_type"`
+	Name          types.String `tfsdk:"name"`
+	Region        types.String `tfsdk:"region"`
+	CloudProvider types.String `tfsdk:"cloud_provider"`
+}
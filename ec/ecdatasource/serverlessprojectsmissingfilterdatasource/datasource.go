@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojectsmissingfilterdatasource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var _ datasource.DataSource = &DataSource{}
+var _ datasource.DataSourceWithConfigure = &DataSource{}
+
+type DataSource struct {
+	client serverless.ClientWithResponsesInterface
+}
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_projects_missing_traffic_filter"
+}
+
+func (d *DataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.client = clients.Serverless
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Prevent panic if the provider has not been configured.
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectType := model.ProjectType.ValueString()
+	projects, err := d.listProjects(ctx, projectType)
+	if err != nil {
+		resp.Diagnostics.AddError(util.APIListFailed, util.OperationDetail("list projects", err.Error()))
+		return
+	}
+
+	missing := make([]projectModelV0, 0)
+	var unchecked []string
+	for _, p := range projects {
+		if p.regionID != model.Region.ValueString() {
+			continue
+		}
+
+		filters, err := d.getProjectTrafficFilters(ctx, projectType, p.id)
+		if err != nil {
+			// A single unreadable project shouldn't hide the result for all the others.
+			unchecked = append(unchecked, fmt.Sprintf("%s: %s", p.id, err))
+			continue
+		}
+
+		if !containsFilter(filters, model.RequiredTrafficFilterID.ValueString()) {
+			missing = append(missing, projectModelV0{
+				ID:   types.StringValue(p.id),
+				Name: types.StringValue(p.name),
+			})
+		}
+	}
+
+	if len(unchecked) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Some projects could not be checked",
+			fmt.Sprintf("The traffic filters of the following projects could not be read, they are not part of the result:\n  - %s", strings.Join(unchecked, "\n  - ")),
+		)
+	}
+
+	projectList, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: projectAttrTypes()}, missing)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.Projects = projectList
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func containsFilter(filters []serverless.TrafficFilter, id string) bool {
+	for _, f := range filters {
+		if f.Id == id {
+			return true
+		}
+	}
+	return false
+}
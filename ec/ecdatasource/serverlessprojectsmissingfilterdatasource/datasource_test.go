@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojectsmissingfilterdatasource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func expectProject(mockClient *mocks.MockClientWithResponsesInterface, id string, filterIDs ...string) {
+	filters := make(serverless.TrafficFilters, 0, len(filterIDs))
+	for _, filterID := range filterIDs {
+		filters = append(filters, serverless.TrafficFilter{Id: filterID})
+	}
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), id).Return(&serverless.GetObservabilityProjectResponse{
+		JSON200:      &serverless.ObservabilityProject{Id: id, TrafficFilters: &filters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+}
+
+func TestRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	d := NewDataSource().(*DataSource)
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	nextPage := "page-2"
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().ListObservabilityProjectsWithResponse(gomock.Any(), &serverless.ListObservabilityProjectsParams{}).Return(&serverless.ListObservabilityProjectsResponse{
+		JSON200: &serverless.ObservabilityProjectList{
+			Items: []serverless.ObservabilityProject{
+				{Id: "compliant", Name: "compliant", RegionId: "aws-us-east-1"},
+				{Id: "missing", Name: "missing", RegionId: "aws-us-east-1"},
+				{Id: "other-region", Name: "other-region", RegionId: "aws-eu-west-1"},
+			},
+			NextPage: &nextPage,
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().ListObservabilityProjectsWithResponse(gomock.Any(), &serverless.ListObservabilityProjectsParams{NextPage: &nextPage}).Return(&serverless.ListObservabilityProjectsResponse{
+		JSON200: &serverless.ObservabilityProjectList{
+			Items: []serverless.ObservabilityProject{
+				{Id: "no-filters", Name: "no-filters", RegionId: "aws-us-east-1"},
+				{Id: "unreadable", Name: "unreadable", RegionId: "aws-us-east-1"},
+			},
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	expectProject(mockClient, "compliant", "other-filter", "required-filter")
+	expectProject(mockClient, "missing", "other-filter")
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "no-filters").Return(&serverless.GetObservabilityProjectResponse{
+		JSON200:      &serverless.ObservabilityProject{Id: "no-filters"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "unreadable").Return(nil, errors.New("connection reset"))
+	d.client = mockClient
+
+	config := modelV0{
+		Region:                  types.StringValue("aws-us-east-1"),
+		ProjectType:             types.StringValue("observability"),
+		RequiredTrafficFilterID: types.StringValue("required-filter"),
+		Projects:                types.ListNull(types.ObjectType{AttrTypes: projectAttrTypes()}),
+	}
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	d.Read(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	require.Equal(t, "Some projects could not be checked", resp.Diagnostics.Warnings()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "unreadable: connection reset")
+
+	var state modelV0
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+
+	var projects []projectModelV0
+	require.False(t, state.Projects.ElementsAs(ctx, &projects, false).HasError())
+	require.Equal(t, []projectModelV0{
+		{ID: types.StringValue("missing"), Name: types.StringValue("missing")},
+		{ID: types.StringValue("no-filters"), Name: types.StringValue("no-filters")},
+	}, projects)
+}
+
+func TestConfigure_UnexpectedProviderData(t *testing.T) {
+	d := &DataSource{}
+	resp := datasource.ConfigureResponse{}
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: "not provider clients"}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Unexpected Provider Data", resp.Diagnostics.Errors()[0].Summary())
+	require.Nil(t, d.client)
+}
@@ -0,0 +1,136 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojectsmissingfilterdatasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+type projectSummary struct {
+	id       string
+	name     string
+	regionID string
+}
+
+// listProjects pages through all projects of the given type. Errors carry the diagnostic
+// detail of the failed request.
+func (d *DataSource) listProjects(ctx context.Context, projectType string) ([]projectSummary, error) {
+	var result []projectSummary
+	var nextPage *string
+
+	for {
+		switch projectType {
+		case "elasticsearch":
+			resp, err := d.client.ListElasticsearchProjectsWithResponse(ctx, &serverless.ListElasticsearchProjectsParams{NextPage: nextPage})
+			if err != nil {
+				return nil, errors.New(util.RequestErrorDetail(err))
+			}
+			if resp.JSON200 == nil {
+				return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+			}
+			for _, p := range resp.JSON200.Items {
+				result = append(result, projectSummary{id: p.Id, name: p.Name, regionID: string(p.RegionId)})
+			}
+			nextPage = resp.JSON200.NextPage
+
+		case "observability":
+			resp, err := d.client.ListObservabilityProjectsWithResponse(ctx, &serverless.ListObservabilityProjectsParams{NextPage: nextPage})
+			if err != nil {
+				return nil, errors.New(util.RequestErrorDetail(err))
+			}
+			if resp.JSON200 == nil {
+				return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+			}
+			for _, p := range resp.JSON200.Items {
+				result = append(result, projectSummary{id: p.Id, name: p.Name, regionID: string(p.RegionId)})
+			}
+			nextPage = resp.JSON200.NextPage
+
+		case "security":
+			resp, err := d.client.ListSecurityProjectsWithResponse(ctx, &serverless.ListSecurityProjectsParams{NextPage: nextPage})
+			if err != nil {
+				return nil, errors.New(util.RequestErrorDetail(err))
+			}
+			if resp.JSON200 == nil {
+				return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+			}
+			for _, p := range resp.JSON200.Items {
+				result = append(result, projectSummary{id: p.Id, name: p.Name, regionID: string(p.RegionId)})
+			}
+			nextPage = resp.JSON200.NextPage
+
+		default:
+			return nil, fmt.Errorf("unknown project type: %s", projectType)
+		}
+
+		if nextPage == nil || *nextPage == "" {
+			return result, nil
+		}
+	}
+}
+
+// getProjectTrafficFilters reads the current traffic filters of a project. Errors carry the
+// diagnostic detail of the failed request.
+func (d *DataSource) getProjectTrafficFilters(ctx context.Context, projectType, projectID string) ([]serverless.TrafficFilter, error) {
+	var filters *serverless.TrafficFilters
+
+	switch projectType {
+	case "elasticsearch":
+		resp, err := d.client.GetElasticsearchProjectWithResponse(ctx, projectID)
+		if err != nil {
+			return nil, errors.New(util.RequestErrorDetail(err))
+		}
+		if resp.JSON200 == nil {
+			return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+		}
+		filters = resp.JSON200.TrafficFilters
+
+	case "observability":
+		resp, err := d.client.GetObservabilityProjectWithResponse(ctx, projectID)
+		if err != nil {
+			return nil, errors.New(util.RequestErrorDetail(err))
+		}
+		if resp.JSON200 == nil {
+			return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+		}
+		filters = resp.JSON200.TrafficFilters
+
+	case "security":
+		resp, err := d.client.GetSecurityProjectWithResponse(ctx, projectID)
+		if err != nil {
+			return nil, errors.New(util.RequestErrorDetail(err))
+		}
+		if resp.JSON200 == nil {
+			return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+		}
+		filters = resp.JSON200.TrafficFilters
+
+	default:
+		return nil, fmt.Errorf("unknown project type: %s", projectType)
+	}
+
+	if filters == nil {
+		return nil, nil
+	}
+	return *filters, nil
+}
@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojectsmissingfilterdatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to find the serverless projects in a region which are not associated with a required traffic filter.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Description: "Only check projects in this region.",
+				Required:    true,
+			},
+			"project_type": schema.StringAttribute{
+				Description: "Only check projects of this type. Must be one of: elasticsearch, observability, security.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("elasticsearch", "observability", "security"),
+				},
+			},
+			"required_traffic_filter_id": schema.StringAttribute{
+				Description: "The ID of the traffic filter every project should be associated with.",
+				Required:    true,
+			},
+
+			// computed fields
+			"projects": projectsSchema(),
+		},
+	}
+}
+
+func projectsSchema() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "The projects which are not associated with the required traffic filter.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description: "The ID of the project.",
+					Computed:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "The name of the project.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func projectAttrTypes() map[string]attr.Type {
+	return projectsSchema().GetType().(types.ListType).ElemType.(types.ObjectType).AttrTypes
+}
+
+type modelV0 struct {
+	Region                  types.String `tfsdk:"region"`
+	ProjectType             types.String `tfsdk:"project_type"`
+	RequiredTrafficFilterID types.String `tfsdk:"required_traffic_filter_id"`
+	Projects                types.List   `tfsdk:"projects"` //< projectModelV0
+}
+
+type projectModelV0 struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
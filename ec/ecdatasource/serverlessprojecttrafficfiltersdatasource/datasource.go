@@ -0,0 +1,144 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojecttrafficfiltersdatasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var _ datasource.DataSource = &DataSource{}
+var _ datasource.DataSourceWithConfigure = &DataSource{}
+
+type DataSource struct {
+	client serverless.ClientWithResponsesInterface
+}
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_project_traffic_filters"
+}
+
+func (d *DataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.client = clients.Serverless
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Prevent panic if the provider has not been configured.
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := model.ProjectID.ValueString()
+	filters, err := d.getProjectTrafficFilters(ctx, model.ProjectType.ValueString(), projectID)
+	if err != nil {
+		resp.Diagnostics.AddError(util.APIReadFailed, util.OperationDetail("read project", err.Error()))
+		return
+	}
+
+	ids := make([]string, 0, len(filters))
+	for _, f := range filters {
+		ids = append(ids, f.Id)
+	}
+	tflog.Info(ctx, "Read project traffic filters", map[string]interface{}{
+		"project_id":         projectID,
+		"traffic_filter_ids": ids,
+	})
+
+	idList, diags := types.ListValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.TrafficFilterIDs = idList
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// getProjectTrafficFilters reads the current traffic filters of a project. Errors carry the
+// diagnostic detail of the failed request.
+func (d *DataSource) getProjectTrafficFilters(ctx context.Context, projectType, projectID string) ([]serverless.TrafficFilter, error) {
+	var filters *serverless.TrafficFilters
+
+	switch projectType {
+	case "elasticsearch":
+		resp, err := d.client.GetElasticsearchProjectWithResponse(ctx, projectID)
+		if err != nil {
+			return nil, errors.New(util.RequestErrorDetail(err))
+		}
+		if resp.JSON200 == nil {
+			return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+		}
+		filters = resp.JSON200.TrafficFilters
+
+	case "observability":
+		resp, err := d.client.GetObservabilityProjectWithResponse(ctx, projectID)
+		if err != nil {
+			return nil, errors.New(util.RequestErrorDetail(err))
+		}
+		if resp.JSON200 == nil {
+			return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+		}
+		filters = resp.JSON200.TrafficFilters
+
+	case "security":
+		resp, err := d.client.GetSecurityProjectWithResponse(ctx, projectID)
+		if err != nil {
+			return nil, errors.New(util.RequestErrorDetail(err))
+		}
+		if resp.JSON200 == nil {
+			return nil, errors.New(util.APIFailureDetail(resp.HTTPResponse, resp.Body))
+		}
+		filters = resp.JSON200.TrafficFilters
+
+	default:
+		return nil, fmt.Errorf("unknown project type: %s", projectType)
+	}
+
+	if filters == nil {
+		return nil, nil
+	}
+	return *filters, nil
+}
@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessprojecttrafficfiltersdatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func readTrafficFilterIDs(t *testing.T, d *DataSource, projectType string) ([]string, datasource.ReadResponse) {
+	ctx := context.Background()
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	config := modelV0{
+		ProjectID:        types.StringValue("project-id"),
+		ProjectType:      types.StringValue(projectType),
+		TrafficFilterIDs: types.ListNull(types.StringType),
+	}
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	d.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		return nil, resp
+	}
+
+	var state modelV0
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	var ids []string
+	require.False(t, state.TrafficFilterIDs.ElementsAs(ctx, &ids, false).HasError())
+	return ids, resp
+}
+
+func TestRead_ReflectsLatestAPIState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	before := serverless.TrafficFilters{{Id: "filter-1"}}
+	after := serverless.TrafficFilters{{Id: "filter-1"}, {Id: "filter-2"}}
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	gomock.InOrder(
+		mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id", TrafficFilters: &before},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id", TrafficFilters: &after},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+	)
+	d := &DataSource{client: mockClient}
+
+	ids, resp := readTrafficFilterIDs(t, d, "observability")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, []string{"filter-1"}, ids)
+
+	// A filter associated outside of Terraform shows up on the next read.
+	ids, resp = readTrafficFilterIDs(t, d, "observability")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, []string{"filter-1", "filter-2"}, ids)
+}
+
+func TestRead_ProjectWithoutFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+		JSON200:      &serverless.SecurityProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	ids, resp := readTrafficFilterIDs(t, &DataSource{client: mockClient}, "security")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Empty(t, ids)
+}
+
+func TestRead_ProjectNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found"},
+	}, nil)
+
+	_, resp := readTrafficFilterIDs(t, &DataSource{client: mockClient}, "elasticsearch")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, util.APIReadFailed, resp.Diagnostics.Errors()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "404 Not Found")
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "retriable: false")
+}
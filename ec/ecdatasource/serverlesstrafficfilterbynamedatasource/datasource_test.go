@@ -0,0 +1,130 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterbynamedatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var testFilters = []serverless.TrafficFilterInfo{
+	{Id: "office-id", Name: "office", Region: "us-east-1", Type: "ip"},
+	{Id: "vpn-1", Name: "vpn", Region: "us-east-1", Type: "ip"},
+	{Id: "vpn-2", Name: "vpn", Region: "us-east-1", Type: "ip"},
+	{Id: "other-region-id", Name: "partners", Region: "eu-west-1", Type: "ip"},
+}
+
+func expectList(mockClient *mocks.MockClientWithResponsesInterface) *gomock.Call {
+	region := "us-east-1"
+	return mockClient.EXPECT().ListTrafficFiltersWithResponse(gomock.Any(), &serverless.ListTrafficFiltersParams{Region: &region}).Return(&serverless.ListTrafficFiltersResponse{
+		JSON200:      &serverless.TrafficFilterList{Items: testFilters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+}
+
+func read(t *testing.T, name string) (modelV0, datasource.ReadResponse) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectList(mockClient)
+	return readWith(t, &DataSource{client: mockClient}, name)
+}
+
+func readWith(t *testing.T, d *DataSource, name string) (modelV0, datasource.ReadResponse) {
+	ctx := context.Background()
+	region := "us-east-1"
+
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	config := modelV0{
+		ID:     types.StringNull(),
+		Region: types.StringValue(region),
+		Name:   types.StringValue(name),
+	}
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	d.Read(ctx, req, &resp)
+
+	var state modelV0
+	if !resp.Diagnostics.HasError() {
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+	}
+	return state, resp
+}
+
+func TestRead_UniqueMatch(t *testing.T) {
+	state, resp := read(t, "office")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, modelV0{
+		ID:     types.StringValue("office-id"),
+		Region: types.StringValue("us-east-1"),
+		Name:   types.StringValue("office"),
+	}, state)
+}
+
+func TestRead_AmbiguousName(t *testing.T) {
+	_, resp := read(t, "vpn")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Ambiguous traffic filter name", resp.Diagnostics.Errors()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "vpn-1, vpn-2")
+}
+
+func TestRead_NotFound(t *testing.T) {
+	// Traffic filters of other regions don't match, even if the API returns them.
+	_, resp := read(t, "partners")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter not found", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func TestRead_ReusesCachedListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectList(mockClient).Times(1)
+	d := &DataSource{client: mockClient, filterLists: serverlessutil.NewFilterListCache(time.Minute)}
+
+	office, resp := readWith(t, d, "office")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, "office-id", office.ID.ValueString())
+
+	_, resp = readWith(t, d, "vpn")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Ambiguous traffic filter name", resp.Diagnostics.Errors()[0].Summary())
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterbynamedatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to resolve the ID of a serverless traffic filter from its region and name, e.g. to associate it with a project without depending on its generated ID.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Description: "The region of the traffic filter.",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the traffic filter. Exactly one traffic filter in the region must have this name.",
+				Required:    true,
+			},
+
+			// computed fields
+			"id": schema.StringAttribute{
+				Description: "The ID of the traffic filter.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type modelV0 struct {
+	ID     types.String `tfsdk:"id"`
+	Region types.String `tfsdk:"region"`
+	Name   types.String `tfsdk:"name"`
+}
@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdatasource
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var _ datasource.DataSource = &DataSource{}
+var _ datasource.DataSourceWithConfigure = &DataSource{}
+
+type DataSource struct {
+	client serverless.ClientWithResponsesInterface
+}
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_traffic_filters"
+}
+
+func (d *DataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.client = clients.Serverless
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Prevent panic if the provider has not been configured.
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := &serverless.ListTrafficFiltersParams{
+		Region: model.Region.ValueStringPointer(),
+	}
+	if model.OnlyDefault.ValueBool() {
+		params.IncludeByDefault = model.OnlyDefault.ValueBoolPointer()
+	}
+
+	listResp, err := d.client.ListTrafficFiltersWithResponse(ctx, params)
+	if err != nil {
+		resp.Diagnostics.AddError(util.APIListFailed, util.OperationDetail("list traffic filters", util.RequestErrorDetail(err)))
+		return
+	}
+
+	if listResp.JSON200 == nil {
+		resp.Diagnostics.AddError(
+			util.APIListFailed,
+			util.OperationDetail("list traffic filters", util.APIFailureDetail(listResp.HTTPResponse, listResp.Body)),
+		)
+		return
+	}
+
+	filters := matchingFilters(listResp.JSON200.Items, model)
+	resp.Diagnostics.Append(modelToState(ctx, filters, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// matchingFilters returns the filters matching all of the configured filters.
+// The region and default status are already sent to the API, but are checked
+// here as well so that the result doesn't depend on server side filtering.
+// The API doesn't support filtering by type, so that's only done client side.
+func matchingFilters(items []serverless.TrafficFilterInfo, model modelV0) []serverless.TrafficFilterInfo {
+	result := make([]serverless.TrafficFilterInfo, 0, len(items))
+	for _, item := range items {
+		if region := model.Region.ValueString(); region != "" && item.Region != region {
+			continue
+		}
+		if model.OnlyDefault.ValueBool() && !item.IncludeByDefault {
+			continue
+		}
+		if filterType := model.Type.ValueString(); filterType != "" && string(item.Type) != filterType {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func modelToState(ctx context.Context, filters []serverless.TrafficFilterInfo, model *modelV0) diag.Diagnostics {
+	result := make([]filterModelV0, 0, len(filters))
+	for _, filter := range filters {
+		m := filterModelV0{
+			ID:               types.StringValue(filter.Id),
+			Name:             types.StringValue(filter.Name),
+			Type:             types.StringValue(string(filter.Type)),
+			Region:           types.StringValue(filter.Region),
+			Description:      types.StringPointerValue(filter.Description),
+			IncludeByDefault: types.BoolValue(filter.IncludeByDefault),
+		}
+
+		if len(filter.Rules) > 0 {
+			m.Rules = make([]ruleModelV0, 0, len(filter.Rules))
+			for _, rule := range filter.Rules {
+				m.Rules = append(m.Rules, ruleModelV0{
+					Source:      types.StringValue(rule.Source),
+					Description: types.StringPointerValue(rule.Description),
+				})
+			}
+			m.RulesList = sortedRules(m.Rules)
+		}
+
+		result = append(result, m)
+	}
+
+	var diags diag.Diagnostics
+	model.Filters, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: filterAttrTypes()}, result)
+	return diags
+}
+
+// sortedRules returns a copy of the rules sorted by source, and by description for equal sources.
+func sortedRules(rules []ruleModelV0) []ruleModelV0 {
+	result := make([]ruleModelV0, len(rules))
+	copy(result, rules)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Source.ValueString() != result[j].Source.ValueString() {
+			return result[i].Source.ValueString() < result[j].Source.ValueString()
+		}
+		return result[i].Description.ValueString() < result[j].Description.ValueString()
+	})
+	return result
+}
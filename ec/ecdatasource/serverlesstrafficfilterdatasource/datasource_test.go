@@ -0,0 +1,177 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var testFilters = []serverless.TrafficFilterInfo{
+	{Id: "ip-default", Name: "ip-default", Region: "us-east-1", Type: "ip", IncludeByDefault: true},
+	{Id: "ip", Name: "ip", Region: "us-east-1", Type: "ip"},
+	{Id: "vpce-default", Name: "vpce-default", Region: "us-east-1", Type: "vpce", IncludeByDefault: true},
+	{Id: "vpce-other-region", Name: "vpce-other-region", Region: "eu-west-1", Type: "vpce"},
+}
+
+func filterIDs(filters []serverless.TrafficFilterInfo) []string {
+	ids := make([]string, 0, len(filters))
+	for _, f := range filters {
+		ids = append(ids, f.Id)
+	}
+	return ids
+}
+
+func TestMatchingFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    modelV0
+		expected []string
+	}{
+		{
+			name:     "returns everything without filters",
+			model:    modelV0{},
+			expected: []string{"ip-default", "ip", "vpce-default", "vpce-other-region"},
+		},
+		{
+			name:     "filters by type only",
+			model:    modelV0{Type: types.StringValue("vpce")},
+			expected: []string{"vpce-default", "vpce-other-region"},
+		},
+		{
+			name: "combines type and region",
+			model: modelV0{
+				Type:   types.StringValue("vpce"),
+				Region: types.StringValue("us-east-1"),
+			},
+			expected: []string{"vpce-default"},
+		},
+		{
+			name: "combines type and only_default",
+			model: modelV0{
+				Type:        types.StringValue("ip"),
+				OnlyDefault: types.BoolValue(true),
+			},
+			expected: []string{"ip-default"},
+		},
+		{
+			name: "returns nothing if no filter matches all conditions",
+			model: modelV0{
+				Type:        types.StringValue("vpce"),
+				Region:      types.StringValue("eu-west-1"),
+				OnlyDefault: types.BoolValue(true),
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, filterIDs(matchingFilters(testFilters, tt.model)))
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	d := NewDataSource().(*DataSource)
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	config := modelV0{
+		Region:      types.StringValue("us-east-1"),
+		OnlyDefault: types.BoolValue(true),
+		Type:        types.StringValue("ip"),
+		Filters:     types.ListNull(types.ObjectType{AttrTypes: filterAttrTypes()}),
+	}
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{
+		Region:           config.Region.ValueStringPointer(),
+		IncludeByDefault: config.OnlyDefault.ValueBoolPointer(),
+	}).Return(&serverless.ListTrafficFiltersResponse{
+		JSON200:      &serverless.TrafficFilterList{Items: testFilters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	d.client = mockClient
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	d.Read(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state modelV0
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+
+	var filters []filterModelV0
+	require.False(t, state.Filters.ElementsAs(ctx, &filters, false).HasError())
+	require.Len(t, filters, 1)
+	require.Equal(t, "ip-default", filters[0].ID.ValueString())
+}
+
+func TestModelToState_RulesListIsSorted(t *testing.T) {
+	ctx := context.Background()
+	office, vpn := "office", "vpn"
+	rules := []serverless.TrafficFilterRule{
+		{Source: "192.168.0.1", Description: &vpn},
+		{Source: "10.0.0.0/8"},
+		{Source: "1.1.1.1", Description: &office},
+	}
+	reversed := []serverless.TrafficFilterRule{rules[2], rules[1], rules[0]}
+
+	var results [][]ruleModelV0
+	for _, apiRules := range [][]serverless.TrafficFilterRule{rules, reversed} {
+		model := modelV0{}
+		diags := modelToState(ctx, []serverless.TrafficFilterInfo{{Id: "ip", Name: "ip", Region: "us-east-1", Type: "ip", Rules: apiRules}}, &model)
+		require.False(t, diags.HasError(), diags)
+
+		var filters []filterModelV0
+		require.False(t, model.Filters.ElementsAs(ctx, &filters, false).HasError())
+		require.Len(t, filters, 1)
+		require.Len(t, filters[0].Rules, 3)
+		results = append(results, filters[0].RulesList)
+	}
+
+	require.Equal(t, []ruleModelV0{
+		{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("office")},
+		{Source: types.StringValue("10.0.0.0/8"), Description: types.StringNull()},
+		{Source: types.StringValue("192.168.0.1"), Description: types.StringValue("vpn")},
+	}, results[0])
+	require.Equal(t, results[0], results[1])
+}
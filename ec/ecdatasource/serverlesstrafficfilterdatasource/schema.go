@@ -0,0 +1,139 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to list the existing serverless traffic filters matching all of the provided filters.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Description: "Only return traffic filters in this region.",
+				Optional:    true,
+			},
+			"only_default": schema.BoolAttribute{
+				Description: "Only return traffic filters which are automatically included in new projects.",
+				Optional:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Only return traffic filters of this type, e.g. `ip` or `vpce`.",
+				Optional:    true,
+			},
+
+			// computed fields
+			"filters": filtersSchema(),
+		},
+	}
+}
+
+func filtersSchema() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "The traffic filters matching the provided filters.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description: "The ID of the traffic filter.",
+					Computed:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "The name of the traffic filter.",
+					Computed:    true,
+				},
+				"type": schema.StringAttribute{
+					Description: "The type of the traffic filter.",
+					Computed:    true,
+				},
+				"region": schema.StringAttribute{
+					Description: "The traffic filter can only be attached to projects in this region.",
+					Computed:    true,
+				},
+				"description": schema.StringAttribute{
+					Description: "The description of the traffic filter.",
+					Computed:    true,
+				},
+				"include_by_default": schema.BoolAttribute{
+					Description: "Should the traffic filter be automatically included in new projects.",
+					Computed:    true,
+				},
+				"rules":      rulesSchema(),
+				"rules_list": rulesListSchema(),
+			},
+		},
+	}
+}
+
+func rulesSchema() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "The rules the traffic filter is made of.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"source": schema.StringAttribute{
+					Description: "Allowed traffic filter source: IP address, CIDR mask, or VPC endpoint ID.",
+					Computed:    true,
+				},
+				"description": schema.StringAttribute{
+					Description: "The description of the rule.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func rulesListSchema() schema.Attribute {
+	rules := rulesSchema().(schema.ListNestedAttribute)
+	rules.Description = "The rules the traffic filter is made of, sorted by source. Unlike rules, which keeps the order of the API, the order is deterministic, so that rules can be referenced by index."
+	return rules
+}
+
+func filterAttrTypes() map[string]attr.Type {
+	return filtersSchema().GetType().(types.ListType).ElemType.(types.ObjectType).AttrTypes
+}
+
+type modelV0 struct {
+	Region      types.String `tfsdk:"region"`
+	OnlyDefault types.Bool   `tfsdk:"only_default"`
+	Type        types.String `tfsdk:"type"`
+	Filters     types.List   `tfsdk:"filters"` //< filterModelV0
+}
+
+type filterModelV0 struct {
+	ID               types.String  `tfsdk:"id"`
+	Name             types.String  `tfsdk:"name"`
+	Type             types.String  `tfsdk:"type"`
+	Region           types.String  `tfsdk:"region"`
+	Description      types.String  `tfsdk:"description"`
+	IncludeByDefault types.Bool    `tfsdk:"include_by_default"`
+	Rules            []ruleModelV0 `tfsdk:"rules"`
+	RulesList        []ruleModelV0 `tfsdk:"rules_list"`
+}
+
+type ruleModelV0 struct {
+	Source      types.String `tfsdk:"source"`
+	Description types.String `tfsdk:"description"`
+}
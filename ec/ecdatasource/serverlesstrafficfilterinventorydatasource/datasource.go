@@ -0,0 +1,203 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterinventorydatasource
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var _ datasource.DataSource = &DataSource{}
+var _ datasource.DataSourceWithConfigure = &DataSource{}
+
+type DataSource struct {
+	client serverless.ClientWithResponsesInterface
+}
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_traffic_filters_inventory"
+}
+
+func (d *DataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.client = clients.Serverless
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Prevent panic if the provider has not been configured.
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var regions []string
+	if !model.Regions.IsNull() {
+		resp.Diagnostics.Append(model.Regions.ElementsAs(ctx, &regions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Traffic filter listings aren't paginated, a single listing returns all regions.
+	listResp, err := d.client.ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{})
+	if err != nil {
+		resp.Diagnostics.AddError(util.APIListFailed, util.OperationDetail("list traffic filters", util.RequestErrorDetail(err)))
+		return
+	}
+	if listResp.JSON200 == nil {
+		resp.Diagnostics.AddError(
+			util.APIListFailed,
+			util.OperationDetail("list traffic filters", util.APIFailureDetail(listResp.HTTPResponse, listResp.Body)),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(modelToState(ctx, newInventory(listResp.JSON200.Items, regions), &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// summary counts traffic filters.
+type summary struct {
+	region       string
+	total        int64
+	defaultCount int64
+	countsByType map[string]int64
+}
+
+func newSummary(region string) summary {
+	return summary{region: region, countsByType: map[string]int64{}}
+}
+
+func (s *summary) add(filter serverless.TrafficFilterInfo) {
+	s.total++
+	if filter.IncludeByDefault {
+		s.defaultCount++
+	}
+	s.countsByType[string(filter.Type)]++
+}
+
+type inventory struct {
+	summary
+	regions []summary
+	filters []serverless.TrafficFilterInfo
+}
+
+// newInventory summarizes the traffic filters in the given regions, or in all regions if none are given.
+// Filtering by region is done client side, so that all regions are summarized from a single listing.
+func newInventory(items []serverless.TrafficFilterInfo, regions []string) inventory {
+	wanted := make(map[string]bool, len(regions))
+	for _, region := range regions {
+		wanted[region] = true
+	}
+
+	result := inventory{summary: newSummary("")}
+	byRegion := map[string]*summary{}
+	for _, item := range items {
+		if len(wanted) > 0 && !wanted[item.Region] {
+			continue
+		}
+		result.add(item)
+		s, ok := byRegion[item.Region]
+		if !ok {
+			regionSummary := newSummary(item.Region)
+			s = &regionSummary
+			byRegion[item.Region] = s
+		}
+		s.add(item)
+		result.filters = append(result.filters, item)
+	}
+
+	for _, s := range byRegion {
+		result.regions = append(result.regions, *s)
+	}
+	sort.Slice(result.regions, func(i, j int) bool { return result.regions[i].region < result.regions[j].region })
+	sort.SliceStable(result.filters, func(i, j int) bool {
+		if result.filters[i].Region != result.filters[j].Region {
+			return result.filters[i].Region < result.filters[j].Region
+		}
+		return result.filters[i].Name < result.filters[j].Name
+	})
+	return result
+}
+
+func modelToState(ctx context.Context, inv inventory, model *modelV0) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.Total = types.Int64Value(inv.total)
+	model.DefaultCount = types.Int64Value(inv.defaultCount)
+	countsByType, d := types.MapValueFrom(ctx, types.Int64Type, inv.countsByType)
+	diags.Append(d...)
+	model.CountsByType = countsByType
+
+	regionSummaries := make([]regionSummaryModelV0, 0, len(inv.regions))
+	for _, s := range inv.regions {
+		countsByType, d := types.MapValueFrom(ctx, types.Int64Type, s.countsByType)
+		diags.Append(d...)
+		regionSummaries = append(regionSummaries, regionSummaryModelV0{
+			Region:       types.StringValue(s.region),
+			Total:        types.Int64Value(s.total),
+			DefaultCount: types.Int64Value(s.defaultCount),
+			CountsByType: countsByType,
+		})
+	}
+	model.RegionSummaries, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: regionSummaryAttrTypes()}, regionSummaries)
+	diags.Append(d...)
+
+	filters := make([]filterModelV0, 0, len(inv.filters))
+	for _, f := range inv.filters {
+		filters = append(filters, filterModelV0{
+			ID:               types.StringValue(f.Id),
+			Name:             types.StringValue(f.Name),
+			Type:             types.StringValue(string(f.Type)),
+			Region:           types.StringValue(f.Region),
+			IncludeByDefault: types.BoolValue(f.IncludeByDefault),
+		})
+	}
+	model.Filters, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: filterAttrTypes()}, filters)
+	diags.Append(d...)
+	return diags
+}
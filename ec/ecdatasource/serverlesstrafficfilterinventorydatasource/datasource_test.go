@@ -0,0 +1,146 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterinventorydatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var testFilters = []serverless.TrafficFilterInfo{
+	{Id: "us-vpce", Name: "vpce", Region: "us-east-1", Type: "vpce", IncludeByDefault: true},
+	{Id: "us-office", Name: "office", Region: "us-east-1", Type: "ip", IncludeByDefault: true},
+	{Id: "us-vpn", Name: "vpn", Region: "us-east-1", Type: "ip"},
+	{Id: "eu-office", Name: "office", Region: "eu-west-1", Type: "ip"},
+	{Id: "gcp-office", Name: "office", Region: "gcp-us-central1", Type: "ip", IncludeByDefault: true},
+}
+
+func TestNewInventory(t *testing.T) {
+	tests := []struct {
+		name     string
+		regions  []string
+		expected inventory
+	}{
+		{
+			name:    "summarizes all regions",
+			regions: nil,
+			expected: inventory{
+				summary: summary{total: 5, defaultCount: 3, countsByType: map[string]int64{"ip": 4, "vpce": 1}},
+				regions: []summary{
+					{region: "eu-west-1", total: 1, defaultCount: 0, countsByType: map[string]int64{"ip": 1}},
+					{region: "gcp-us-central1", total: 1, defaultCount: 1, countsByType: map[string]int64{"ip": 1}},
+					{region: "us-east-1", total: 3, defaultCount: 2, countsByType: map[string]int64{"ip": 2, "vpce": 1}},
+				},
+				filters: []serverless.TrafficFilterInfo{testFilters[3], testFilters[4], testFilters[1], testFilters[0], testFilters[2]},
+			},
+		},
+		{
+			name:    "only summarizes the given regions",
+			regions: []string{"us-east-1", "eu-west-1", "unknown"},
+			expected: inventory{
+				summary: summary{total: 4, defaultCount: 2, countsByType: map[string]int64{"ip": 3, "vpce": 1}},
+				regions: []summary{
+					{region: "eu-west-1", total: 1, defaultCount: 0, countsByType: map[string]int64{"ip": 1}},
+					{region: "us-east-1", total: 3, defaultCount: 2, countsByType: map[string]int64{"ip": 2, "vpce": 1}},
+				},
+				filters: []serverless.TrafficFilterInfo{testFilters[3], testFilters[1], testFilters[0], testFilters[2]},
+			},
+		},
+		{
+			name:     "nothing to summarize",
+			regions:  []string{"unknown"},
+			expected: inventory{summary: summary{countsByType: map[string]int64{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, newInventory(testFilters, tt.regions))
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	d := NewDataSource().(*DataSource)
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	config := modelV0{
+		Regions:         types.SetValueMust(types.StringType, []attr.Value{types.StringValue("us-east-1")}),
+		Total:           types.Int64Null(),
+		DefaultCount:    types.Int64Null(),
+		CountsByType:    types.MapNull(types.Int64Type),
+		RegionSummaries: types.ListNull(types.ObjectType{AttrTypes: regionSummaryAttrTypes()}),
+		Filters:         types.ListNull(types.ObjectType{AttrTypes: filterAttrTypes()}),
+	}
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{}).Return(&serverless.ListTrafficFiltersResponse{
+		JSON200:      &serverless.TrafficFilterList{Items: testFilters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	d.client = mockClient
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	d.Read(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state modelV0
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, int64(3), state.Total.ValueInt64())
+	require.Equal(t, int64(2), state.DefaultCount.ValueInt64())
+	require.Equal(t, types.MapValueMust(types.Int64Type, map[string]attr.Value{
+		"ip":   types.Int64Value(2),
+		"vpce": types.Int64Value(1),
+	}), state.CountsByType)
+
+	var regionSummaries []regionSummaryModelV0
+	require.False(t, state.RegionSummaries.ElementsAs(ctx, &regionSummaries, false).HasError())
+	require.Len(t, regionSummaries, 1)
+	require.Equal(t, "us-east-1", regionSummaries[0].Region.ValueString())
+	require.Equal(t, int64(3), regionSummaries[0].Total.ValueInt64())
+
+	var filters []filterModelV0
+	require.False(t, state.Filters.ElementsAs(ctx, &filters, false).HasError())
+	require.Len(t, filters, 3)
+	require.Equal(t, "us-office", filters[0].ID.ValueString())
+}
@@ -0,0 +1,148 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterinventorydatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to summarize the serverless traffic filters, e.g. for dashboards: the number of traffic filters by type and default status, overall and per region, along with the traffic filters themselves.",
+		Attributes: map[string]schema.Attribute{
+			"regions": schema.SetAttribute{
+				Description: "Only summarize traffic filters in these regions. All regions are summarized if not set.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+
+			// computed fields
+			"total": schema.Int64Attribute{
+				Description: "The number of traffic filters.",
+				Computed:    true,
+			},
+			"default_count": schema.Int64Attribute{
+				Description: "The number of traffic filters which are automatically included in new projects.",
+				Computed:    true,
+			},
+			"counts_by_type":   countsByTypeSchema(),
+			"region_summaries": regionSummariesSchema(),
+			"filters":          filtersSchema(),
+		},
+	}
+}
+
+func countsByTypeSchema() schema.Attribute {
+	return schema.MapAttribute{
+		Description: "The number of traffic filters by type, e.g. `ip` or `vpce`.",
+		ElementType: types.Int64Type,
+		Computed:    true,
+	}
+}
+
+func regionSummariesSchema() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "The number of traffic filters per region, sorted by region.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"region": schema.StringAttribute{
+					Description: "The region.",
+					Computed:    true,
+				},
+				"total": schema.Int64Attribute{
+					Description: "The number of traffic filters in the region.",
+					Computed:    true,
+				},
+				"default_count": schema.Int64Attribute{
+					Description: "The number of traffic filters in the region which are automatically included in new projects.",
+					Computed:    true,
+				},
+				"counts_by_type": countsByTypeSchema(),
+			},
+		},
+	}
+}
+
+func filtersSchema() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "The summarized traffic filters, sorted by region and name.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description: "The ID of the traffic filter.",
+					Computed:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "The name of the traffic filter.",
+					Computed:    true,
+				},
+				"type": schema.StringAttribute{
+					Description: "The type of the traffic filter.",
+					Computed:    true,
+				},
+				"region": schema.StringAttribute{
+					Description: "The region of the traffic filter.",
+					Computed:    true,
+				},
+				"include_by_default": schema.BoolAttribute{
+					Description: "Should the traffic filter be automatically included in new projects.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func regionSummaryAttrTypes() map[string]attr.Type {
+	return regionSummariesSchema().GetType().(types.ListType).ElemType.(types.ObjectType).AttrTypes
+}
+
+func filterAttrTypes() map[string]attr.Type {
+	return filtersSchema().GetType().(types.ListType).ElemType.(types.ObjectType).AttrTypes
+}
+
+type modelV0 struct {
+	Regions         types.Set   `tfsdk:"regions"`
+	Total           types.Int64 `tfsdk:"total"`
+	DefaultCount    types.Int64 `tfsdk:"default_count"`
+	CountsByType    types.Map   `tfsdk:"counts_by_type"`
+	RegionSummaries types.List  `tfsdk:"region_summaries"` //< regionSummaryModelV0
+	Filters         types.List  `tfsdk:"filters"`          //< filterModelV0
+}
+
+type regionSummaryModelV0 struct {
+	Region       types.String `tfsdk:"region"`
+	Total        types.Int64  `tfsdk:"total"`
+	DefaultCount types.Int64  `tfsdk:"default_count"`
+	CountsByType types.Map    `tfsdk:"counts_by_type"`
+}
+
+type filterModelV0 struct {
+	ID               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Type             types.String `tfsdk:"type"`
+	Region           types.String `tfsdk:"region"`
+	IncludeByDefault types.Bool   `tfsdk:"include_by_default"`
+}
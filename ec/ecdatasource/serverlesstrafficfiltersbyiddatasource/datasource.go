@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfiltersbyiddatasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+// maxConcurrentReads limits how many traffic filters are read at the same time.
+const maxConcurrentReads = 5
+
+var _ datasource.DataSource = &DataSource{}
+var _ datasource.DataSourceWithConfigure = &DataSource{}
+
+type DataSource struct {
+	client serverless.ClientWithResponsesInterface
+}
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+func (d *DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_traffic_filters_by_id"
+}
+
+func (d *DataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.client = clients.Serverless
+}
+
+func (d *DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Prevent panic if the provider has not been configured.
+	if d.client == nil {
+		resp.Diagnostics.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ids []string
+	resp.Diagnostics.Append(model.IDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filters, diags := d.readFilters(ctx, ids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	missing := missingIDs(filters)
+	if model.FailOnMissing.ValueBool() && len(missing) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ids"),
+			"Traffic filters not found",
+			fmt.Sprintf("%d of the traffic filters don't exist: %s", len(missing), strings.Join(missing, ", ")),
+		)
+		return
+	}
+
+	model.MissingIDs, diags = types.ListValueFrom(ctx, types.StringType, missing)
+	resp.Diagnostics.Append(diags...)
+
+	model.Filters, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: filterAttrTypes()}, filters)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// readFilters reads the traffic filters with the given IDs concurrently, at most maxConcurrentReads
+// at a time. The result keeps the order of the IDs, traffic filters which don't exist are marked as
+// not found, while other failures are returned as errors.
+func (d *DataSource) readFilters(ctx context.Context, ids []string) ([]filterModelV0, diag.Diagnostics) {
+	filters := make([]filterModelV0, len(ids))
+	results := make([]diag.Diagnostics, len(ids))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentReads)
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			filters[i], results[i] = d.readFilter(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	var diags diag.Diagnostics
+	for _, result := range results {
+		diags.Append(result...)
+	}
+	return filters, diags
+}
+
+func missingIDs(filters []filterModelV0) []string {
+	missing := []string{}
+	for _, f := range filters {
+		if !f.Found.ValueBool() {
+			missing = append(missing, f.ID.ValueString())
+		}
+	}
+	return missing
+}
+
+func (d *DataSource) readFilter(ctx context.Context, id string) (filterModelV0, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	notFound := filterModelV0{
+		ID:               types.StringValue(id),
+		Found:            types.BoolValue(false),
+		Name:             types.StringNull(),
+		Type:             types.StringNull(),
+		Region:           types.StringNull(),
+		Description:      types.StringNull(),
+		IncludeByDefault: types.BoolNull(),
+	}
+
+	resp, err := d.client.GetTrafficFilterWithResponse(ctx, id)
+	if err != nil {
+		diags.AddError(util.APIReadFailed, util.OperationDetail("read traffic filter "+id, util.RequestErrorDetail(err)))
+		return notFound, diags
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return notFound, diags
+	}
+	if resp.JSON200 == nil {
+		diags.AddError(
+			util.APIReadFailed,
+			util.OperationDetail("read traffic filter "+id, util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+		)
+		return notFound, diags
+	}
+
+	filter := resp.JSON200
+	m := filterModelV0{
+		ID:               types.StringValue(id),
+		Found:            types.BoolValue(true),
+		Name:             types.StringValue(filter.Name),
+		Type:             types.StringValue(string(filter.Type)),
+		Region:           types.StringValue(filter.Region),
+		Description:      types.StringPointerValue(filter.Description),
+		IncludeByDefault: types.BoolValue(filter.IncludeByDefault),
+	}
+	for _, rule := range filter.Rules {
+		m.Rules = append(m.Rules, ruleModelV0{
+			Source:      types.StringValue(rule.Source),
+			Description: types.StringPointerValue(rule.Description),
+		})
+	}
+	return m, diags
+}
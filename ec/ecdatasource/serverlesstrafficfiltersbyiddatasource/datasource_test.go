@@ -0,0 +1,181 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfiltersbyiddatasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func read(t *testing.T, client serverless.ClientWithResponsesInterface, ids ...string) (modelV0, datasource.ReadResponse) {
+	return readWith(t, client, false, ids...)
+}
+
+func readWith(t *testing.T, client serverless.ClientWithResponsesInterface, failOnMissing bool, ids ...string) (modelV0, datasource.ReadResponse) {
+	ctx := context.Background()
+
+	d := &DataSource{client: client}
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	values := make([]attr.Value, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, types.StringValue(id))
+	}
+	config := modelV0{
+		IDs:           types.ListValueMust(types.StringType, values),
+		FailOnMissing: types.BoolValue(failOnMissing),
+		MissingIDs:    types.ListNull(types.StringType),
+		Filters:       types.ListNull(types.ObjectType{AttrTypes: filterAttrTypes()}),
+	}
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, config, schemaResp.Schema.Type()),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	d.Read(ctx, req, &resp)
+
+	var state modelV0
+	if !resp.Diagnostics.HasError() {
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+	}
+	return state, resp
+}
+
+func expectFilter(mockClient *mocks.MockClientWithResponsesInterface, id string, filter *serverless.TrafficFilterInfo) {
+	resp := &serverless.GetTrafficFilterResponse{
+		JSON200:      filter,
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}
+	if filter == nil {
+		resp.HTTPResponse = &http.Response{StatusCode: http.StatusNotFound}
+	}
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), id).Return(resp, nil)
+}
+
+func TestRead_ExistingAndMissingFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	office := "office"
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectFilter(mockClient, "office-id", &serverless.TrafficFilterInfo{
+		Id:     "office-id",
+		Name:   "office",
+		Region: "us-east-1",
+		Type:   "ip",
+		Rules:  []serverless.TrafficFilterRule{{Source: "1.1.1.1", Description: &office}},
+	})
+	expectFilter(mockClient, "missing-id", nil)
+	expectFilter(mockClient, "vpce-id", &serverless.TrafficFilterInfo{
+		Id:               "vpce-id",
+		Name:             "vpce",
+		Region:           "us-east-1",
+		Type:             "vpce",
+		IncludeByDefault: true,
+		Rules:            []serverless.TrafficFilterRule{{Source: "vpce-1"}},
+	})
+
+	state, resp := read(t, mockClient, "office-id", "missing-id", "vpce-id")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("missing-id")}), state.MissingIDs)
+
+	var filters []filterModelV0
+	require.False(t, state.Filters.ElementsAs(ctx, &filters, false).HasError())
+	require.Equal(t, []filterModelV0{
+		{
+			ID:               types.StringValue("office-id"),
+			Found:            types.BoolValue(true),
+			Name:             types.StringValue("office"),
+			Type:             types.StringValue("ip"),
+			Region:           types.StringValue("us-east-1"),
+			Description:      types.StringNull(),
+			IncludeByDefault: types.BoolValue(false),
+			Rules:            []ruleModelV0{{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("office")}},
+		},
+		{
+			ID:               types.StringValue("missing-id"),
+			Found:            types.BoolValue(false),
+			Name:             types.StringNull(),
+			Type:             types.StringNull(),
+			Region:           types.StringNull(),
+			Description:      types.StringNull(),
+			IncludeByDefault: types.BoolNull(),
+		},
+		{
+			ID:               types.StringValue("vpce-id"),
+			Found:            types.BoolValue(true),
+			Name:             types.StringValue("vpce"),
+			Type:             types.StringValue("vpce"),
+			Region:           types.StringValue("us-east-1"),
+			Description:      types.StringNull(),
+			IncludeByDefault: types.BoolValue(true),
+			Rules:            []ruleModelV0{{Source: types.StringValue("vpce-1"), Description: types.StringNull()}},
+		},
+	}, filters)
+}
+
+func TestRead_FailsOnOtherErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectFilter(mockClient, "office-id", &serverless.TrafficFilterInfo{Id: "office-id"})
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "broken-id").Return(&serverless.GetTrafficFilterResponse{
+		Body:         []byte(`{"error":"internal"}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error"},
+	}, nil)
+
+	_, resp := read(t, mockClient, "office-id", "broken-id")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Len(t, resp.Diagnostics.Errors(), 1)
+	require.Equal(t, util.APIReadFailed, resp.Diagnostics.Errors()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "broken-id")
+}
+
+func TestRead_FailOnMissingReportsAllMissingIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectFilter(mockClient, "office-id", &serverless.TrafficFilterInfo{Id: "office-id"})
+	expectFilter(mockClient, "missing-1", nil)
+	expectFilter(mockClient, "missing-2", nil)
+
+	_, resp := readWith(t, mockClient, true, "missing-1", "office-id", "missing-2")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Len(t, resp.Diagnostics.Errors(), 1)
+	require.Equal(t, "Traffic filters not found", resp.Diagnostics.Errors()[0].Summary())
+	require.Equal(t, "2 of the traffic filters don't exist: missing-1, missing-2", resp.Diagnostics.Errors()[0].Detail())
+}
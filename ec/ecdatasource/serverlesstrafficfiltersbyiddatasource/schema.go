@@ -0,0 +1,134 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfiltersbyiddatasource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (d *DataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to read several serverless traffic filters by ID at once. Traffic filters which don't exist are returned with found set to false instead of failing, unless fail_on_missing is set. This allows checking that all traffic filters referenced by a configuration exist before associating any of them.",
+		Attributes: map[string]schema.Attribute{
+			"ids": schema.ListAttribute{
+				Description: "The IDs of the traffic filters.",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"fail_on_missing": schema.BoolAttribute{
+				Description: "Fail with a single error listing all of the traffic filters which don't exist. Defaults to false.",
+				Optional:    true,
+			},
+
+			// computed fields
+			"missing_ids": schema.ListAttribute{
+				Description: "The IDs of the traffic filters which don't exist, in the order of ids.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"filters": filtersSchema(),
+		},
+	}
+}
+
+func filtersSchema() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "The traffic filters, in the order of ids.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description: "The ID of the traffic filter.",
+					Computed:    true,
+				},
+				"found": schema.BoolAttribute{
+					Description: "Whether the traffic filter exists. The other attributes are null if it doesn't.",
+					Computed:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "The name of the traffic filter.",
+					Computed:    true,
+				},
+				"type": schema.StringAttribute{
+					Description: "The type of the traffic filter.",
+					Computed:    true,
+				},
+				"region": schema.StringAttribute{
+					Description: "The traffic filter can only be attached to projects in this region.",
+					Computed:    true,
+				},
+				"description": schema.StringAttribute{
+					Description: "The description of the traffic filter.",
+					Computed:    true,
+				},
+				"include_by_default": schema.BoolAttribute{
+					Description: "Should the traffic filter be automatically included in new projects.",
+					Computed:    true,
+				},
+				"rules": schema.ListNestedAttribute{
+					Description: "The rules the traffic filter is made of.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"source": schema.StringAttribute{
+								Description: "Allowed traffic filter source: IP address, CIDR mask, or VPC endpoint ID.",
+								Computed:    true,
+							},
+							"description": schema.StringAttribute{
+								Description: "The description of the rule.",
+								Computed:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func filterAttrTypes() map[string]attr.Type {
+	return filtersSchema().GetType().(types.ListType).ElemType.(types.ObjectType).AttrTypes
+}
+
+type modelV0 struct {
+	IDs           types.List `tfsdk:"ids"`
+	FailOnMissing types.Bool `tfsdk:"fail_on_missing"`
+	MissingIDs    types.List `tfsdk:"missing_ids"`
+	Filters       types.List `tfsdk:"filters"` //< filterModelV0
+}
+
+type filterModelV0 struct {
+	ID               types.String  `tfsdk:"id"`
+	Found            types.Bool    `tfsdk:"found"`
+	Name             types.String  `tfsdk:"name"`
+	Type             types.String  `tfsdk:"type"`
+	Region           types.String  `tfsdk:"region"`
+	Description      types.String  `tfsdk:"description"`
+	IncludeByDefault types.Bool    `tfsdk:"include_by_default"`
+	Rules            []ruleModelV0 `tfsdk:"rules"`
+}
+
+type ruleModelV0 struct {
+	Source      types.String `tfsdk:"source"`
+	Description types.String `tfsdk:"description"`
+}
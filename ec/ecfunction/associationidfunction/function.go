@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package associationidfunction
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterassocresource"
+)
+
+var _ function.Function = &Function{}
+
+type Function struct{}
+
+func NewFunction() function.Function {
+	return &Function{}
+}
+
+func (f *Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "association_id"
+}
+
+func (f *Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the ID of a serverless traffic filter association",
+		Description: "Returns the ID of the `ec_serverless_traffic_filter_association` resource associating the given traffic filter with the given project.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "project_id",
+				Description: "The ID of the serverless project.",
+			},
+			function.StringParameter{
+				Name:        "traffic_filter_id",
+				Description: "The ID of the serverless traffic filter.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var projectID, trafficFilterID string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &projectID, &trafficFilterID))
+	if resp.Error != nil {
+		return
+	}
+
+	id := serverlesstrafficfilterassocresource.AssociationID(projectID, trafficFilterID)
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, id))
+}
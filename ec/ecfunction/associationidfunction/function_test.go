@@ -0,0 +1,49 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package associationidfunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterassocresource"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.StringValue("project-id"),
+			types.StringValue("filter-id"),
+		}),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+	NewFunction().Run(ctx, req, &resp)
+
+	require.Nil(t, resp.Error)
+	require.Equal(t, types.StringValue("project-id,filter-id"), resp.Result.Value())
+	require.Equal(t, types.StringValue(serverlesstrafficfilterassocresource.AssociationID("project-id", "filter-id")), resp.Result.Value())
+}
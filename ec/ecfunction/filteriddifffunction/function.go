@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filteriddifffunction
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterdefaultsresource"
+)
+
+var _ function.Function = &Function{}
+
+var resultAttrTypes = map[string]attr.Type{
+	"to_add":    types.ListType{ElemType: types.StringType},
+	"to_remove": types.ListType{ElemType: types.StringType},
+}
+
+type Function struct{}
+
+func NewFunction() function.Function {
+	return &Function{}
+}
+
+func (f *Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "filter_id_diff"
+}
+
+func (f *Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Computes the difference between desired and current traffic filter IDs",
+		Description: "Returns an object with a `to_add` list of the IDs which are desired but not current, and a `to_remove` list of the IDs which are current but not desired, " +
+			"in the order of the arguments. It's computed the same way as the changes applied by the `ec_serverless_traffic_filter_defaults` resource, " +
+			"which allows previewing them.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "desired",
+				Description: "The desired traffic filter IDs.",
+				ElementType: types.StringType,
+			},
+			function.ListParameter{
+				Name:        "current",
+				Description: "The current traffic filter IDs.",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: resultAttrTypes},
+	}
+}
+
+func (f *Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var desired, current []string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &desired, &current))
+	if resp.Error != nil {
+		return
+	}
+
+	toAdd, toRemove := serverlesstrafficfilterdefaultsresource.DiffFilterIDs(desired, current)
+	result, diags := types.ObjectValue(resultAttrTypes, map[string]attr.Value{
+		"to_add":    listValue(toAdd),
+		"to_remove": listValue(toRemove),
+	})
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
+
+func listValue(ids []string) types.List {
+	elems := make([]attr.Value, 0, len(ids))
+	for _, id := range ids {
+		elems = append(elems, types.StringValue(id))
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filteriddifffunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  []string
+		current  []string
+		toAdd    []string
+		toRemove []string
+	}{
+		{
+			name:     "overlapping IDs",
+			desired:  []string{"a", "b", "c"},
+			current:  []string{"b", "c", "d"},
+			toAdd:    []string{"a"},
+			toRemove: []string{"d"},
+		},
+		{
+			name:     "disjoint IDs",
+			desired:  []string{"a", "b"},
+			current:  []string{"c", "d"},
+			toAdd:    []string{"a", "b"},
+			toRemove: []string{"c", "d"},
+		},
+		{
+			name:     "identical IDs",
+			desired:  []string{"a", "b"},
+			current:  []string{"b", "a"},
+			toAdd:    []string{},
+			toRemove: []string{},
+		},
+		{
+			name:     "duplicate IDs",
+			desired:  []string{"a", "a"},
+			current:  []string{},
+			toAdd:    []string{"a"},
+			toRemove: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{listValue(tt.desired), listValue(tt.current)}),
+			}
+			resp := function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(resultAttrTypes)),
+			}
+			NewFunction().Run(context.Background(), req, &resp)
+
+			require.Nil(t, resp.Error)
+			require.Equal(t, types.ObjectValueMust(resultAttrTypes, map[string]attr.Value{
+				"to_add":    listValue(tt.toAdd),
+				"to_remove": listValue(tt.toRemove),
+			}), resp.Result.Value())
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package mergerulesfunction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterresource"
+)
+
+var _ function.Function = &Function{}
+
+var ruleType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"source":      types.StringType,
+	"description": types.StringType,
+}}
+
+type Function struct{}
+
+func NewFunction() function.Function {
+	return &Function{}
+}
+
+func (f *Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "merge_rules"
+}
+
+func (f *Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Merges two sets of serverless traffic filter rules",
+		Description: "Returns the union of two lists or sets of rule objects, shaped like the `rule` blocks of the `ec_serverless_traffic_filter` resource. " +
+			"Rules with the same source, once normalized, are merged into one, keeping the first non-empty description. " +
+			"The rules of `a` come first, followed by the rules of `b` with new sources.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "a",
+				Description: "The first rules, as a list or set of objects with a `source` and an optional `description` attribute.",
+			},
+			function.DynamicParameter{
+				Name:        "b",
+				Description: "The second rules, as a list or set of objects with a `source` and an optional `description` attribute.",
+			},
+		},
+		Return: function.ListReturn{ElementType: ruleType},
+	}
+}
+
+func (f *Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b types.Dynamic
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	ruleSets := make([][]serverlesstrafficfilterresource.TrafficFilterRuleSpec, 0, 2)
+	for i, arg := range []types.Dynamic{a, b} {
+		if arg.IsNull() || arg.IsUnderlyingValueNull() {
+			resp.Error = function.NewArgumentFuncError(int64(i), "The rules must not be null")
+			return
+		}
+
+		value, err := arg.UnderlyingValue().ToTerraformValue(ctx)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(int64(i), err.Error())
+			return
+		}
+
+		rules, err := rulesFromValue(value)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(int64(i), fmt.Sprintf("Invalid rules: %s", err))
+			return
+		}
+		ruleSets = append(ruleSets, rules)
+	}
+
+	merged := serverlesstrafficfilterresource.MergeRules(ruleSets...)
+	elems := make([]attr.Value, 0, len(merged))
+	for _, rule := range merged {
+		description := types.StringNull()
+		if rule.Description != "" {
+			description = types.StringValue(rule.Description)
+		}
+		elems = append(elems, types.ObjectValueMust(ruleType.AttrTypes, map[string]attr.Value{
+			"source":      types.StringValue(rule.Source),
+			"description": description,
+		}))
+	}
+
+	result, diags := types.ListValue(ruleType, elems)
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
+
+func rulesFromValue(value tftypes.Value) ([]serverlesstrafficfilterresource.TrafficFilterRuleSpec, error) {
+	if !value.Type().Is(tftypes.List{}) && !value.Type().Is(tftypes.Set{}) && !value.Type().Is(tftypes.Tuple{}) {
+		return nil, fmt.Errorf("expected a list or set, got %s", value.Type())
+	}
+	var elems []tftypes.Value
+	if err := value.As(&elems); err != nil {
+		return nil, err
+	}
+
+	rules := make([]serverlesstrafficfilterresource.TrafficFilterRuleSpec, 0, len(elems))
+	for i, elem := range elems {
+		if !elem.Type().Is(tftypes.Object{}) && !elem.Type().Is(tftypes.Map{}) {
+			return nil, fmt.Errorf("rule[%d]: expected an object or map, got %s", i, elem.Type())
+		}
+		var attrs map[string]tftypes.Value
+		if err := elem.As(&attrs); err != nil {
+			return nil, fmt.Errorf("rule[%d]: %w", i, err)
+		}
+
+		var r serverlesstrafficfilterresource.TrafficFilterRuleSpec
+		var err error
+		if r.Source, err = stringValue(attrs["source"]); err != nil {
+			return nil, fmt.Errorf("rule[%d].source: %w", i, err)
+		}
+		if r.Source == "" {
+			return nil, fmt.Errorf("rule[%d].source: must not be empty", i)
+		}
+		if r.Description, err = stringValue(attrs["description"]); err != nil {
+			return nil, fmt.Errorf("rule[%d].description: %w", i, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// stringValue returns the value of a string, treating missing and null values
+// as empty strings.
+func stringValue(value tftypes.Value) (string, error) {
+	if value.Type() == nil || value.IsNull() {
+		return "", nil
+	}
+	if !value.Type().Is(tftypes.String) {
+		return "", fmt.Errorf("expected a string, got %s", value.Type())
+	}
+	var s string
+	if err := value.As(&s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
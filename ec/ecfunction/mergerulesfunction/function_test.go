@@ -0,0 +1,85 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package mergerulesfunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func ruleValue(source string, description types.String) attr.Value {
+	return types.ObjectValueMust(ruleType.AttrTypes, map[string]attr.Value{
+		"source":      types.StringValue(source),
+		"description": description,
+	})
+}
+
+func run(t *testing.T, a, b types.Dynamic) function.RunResponse {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{a, b}),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.ListUnknown(ruleType)),
+	}
+	NewFunction().Run(context.Background(), req, &resp)
+	return resp
+}
+
+func TestRun_OverlappingSources(t *testing.T) {
+	a := types.DynamicValue(types.ListValueMust(ruleType, []attr.Value{
+		ruleValue("1.1.1.1", types.StringNull()),
+		ruleValue("10.0.0.0/8", types.StringValue("office")),
+	}))
+	b := types.DynamicValue(types.SetValueMust(ruleType, []attr.Value{
+		ruleValue("1.1.1.1", types.StringValue("vpn")),
+		ruleValue("10.0.0.0/8", types.StringValue("datacenter")),
+		ruleValue("2.2.2.2", types.StringNull()),
+	}))
+
+	resp := run(t, a, b)
+	require.Nil(t, resp.Error)
+	require.Equal(t, types.ListValueMust(ruleType, []attr.Value{
+		ruleValue("1.1.1.1", types.StringValue("vpn")),
+		ruleValue("10.0.0.0/8", types.StringValue("office")),
+		ruleValue("2.2.2.2", types.StringNull()),
+	}), resp.Result.Value())
+}
+
+func TestRun_EmptyRules(t *testing.T) {
+	empty := types.DynamicValue(types.ListValueMust(ruleType, []attr.Value{}))
+	resp := run(t, empty, empty)
+	require.Nil(t, resp.Error)
+	require.Equal(t, types.ListValueMust(ruleType, []attr.Value{}), resp.Result.Value())
+}
+
+func TestRun_InvalidRules(t *testing.T) {
+	valid := types.DynamicValue(types.ListValueMust(ruleType, []attr.Value{}))
+
+	resp := run(t, valid, types.DynamicValue(types.StringValue("1.1.1.1")))
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Error(), "expected a list or set")
+
+	resp = run(t, types.DynamicNull(), valid)
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Error(), "must not be null")
+}
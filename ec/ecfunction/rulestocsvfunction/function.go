@@ -0,0 +1,156 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rulestocsvfunction
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ function.Function = &Function{}
+
+type Function struct{}
+
+func NewFunction() function.Function {
+	return &Function{}
+}
+
+func (f *Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "rules_to_csv"
+}
+
+func (f *Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Renders serverless traffic filter rules as CSV",
+		Description: "Renders a list or set of rule objects, shaped like the `rule` blocks of the `ec_serverless_traffic_filter` resource, " +
+			"as a CSV string with a `source,description` header row. Values containing commas, quotes or line breaks are quoted.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "rules",
+				Description: "The rules, as a list or set of objects with a `source` and an optional `description` attribute.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arg types.Dynamic
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &arg))
+	if resp.Error != nil {
+		return
+	}
+
+	if arg.IsNull() || arg.IsUnderlyingValueNull() {
+		resp.Error = function.NewArgumentFuncError(0, "The rules must not be null")
+		return
+	}
+
+	value, err := arg.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	rules, err := rulesFromValue(value)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid rules: %s", err))
+		return
+	}
+
+	result, err := rulesToCSV(rules)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
+
+type rule struct {
+	source      string
+	description string
+}
+
+func rulesToCSV(rules []rule) (string, error) {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	if err := w.Write([]string{"source", "description"}); err != nil {
+		return "", err
+	}
+	for _, r := range rules {
+		if err := w.Write([]string{r.source, r.description}); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
+
+func rulesFromValue(value tftypes.Value) ([]rule, error) {
+	if !value.Type().Is(tftypes.List{}) && !value.Type().Is(tftypes.Set{}) && !value.Type().Is(tftypes.Tuple{}) {
+		return nil, fmt.Errorf("expected a list or set, got %s", value.Type())
+	}
+	var elems []tftypes.Value
+	if err := value.As(&elems); err != nil {
+		return nil, err
+	}
+
+	rules := make([]rule, 0, len(elems))
+	for i, elem := range elems {
+		if !elem.Type().Is(tftypes.Object{}) && !elem.Type().Is(tftypes.Map{}) {
+			return nil, fmt.Errorf("rule[%d]: expected an object or map, got %s", i, elem.Type())
+		}
+		var attrs map[string]tftypes.Value
+		if err := elem.As(&attrs); err != nil {
+			return nil, fmt.Errorf("rule[%d]: %w", i, err)
+		}
+
+		var r rule
+		var err error
+		if r.source, err = stringValue(attrs["source"]); err != nil {
+			return nil, fmt.Errorf("rule[%d].source: %w", i, err)
+		}
+		if r.description, err = stringValue(attrs["description"]); err != nil {
+			return nil, fmt.Errorf("rule[%d].description: %w", i, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// stringValue returns the value of a string, treating missing and null values
+// as empty strings.
+func stringValue(value tftypes.Value) (string, error) {
+	if value.Type() == nil || value.IsNull() {
+		return "", nil
+	}
+	if !value.Type().Is(tftypes.String) {
+		return "", fmt.Errorf("expected a string, got %s", value.Type())
+	}
+	var s string
+	if err := value.As(&s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
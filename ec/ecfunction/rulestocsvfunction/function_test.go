@@ -0,0 +1,112 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rulestocsvfunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+var ruleType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"source":      types.StringType,
+	"description": types.StringType,
+}}
+
+func ruleValue(source string, description types.String) attr.Value {
+	return types.ObjectValueMust(ruleType.AttrTypes, map[string]attr.Value{
+		"source":      types.StringValue(source),
+		"description": description,
+	})
+}
+
+func run(t *testing.T, arg types.Dynamic) function.RunResponse {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{arg}),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+	NewFunction().Run(context.Background(), req, &resp)
+	return resp
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      types.Dynamic
+		expected string
+	}{
+		{
+			name: "plain descriptions",
+			arg: types.DynamicValue(types.ListValueMust(ruleType, []attr.Value{
+				ruleValue("1.1.1.1", types.StringValue("vpn")),
+				ruleValue("2.2.2.0/24", types.StringNull()),
+			})),
+			expected: "source,description\n1.1.1.1,vpn\n2.2.2.0/24,\n",
+		},
+		{
+			name: "descriptions with commas and quotes",
+			arg: types.DynamicValue(types.SetValueMust(ruleType, []attr.Value{
+				ruleValue("1.1.1.1", types.StringValue("office, floor 2")),
+				ruleValue("2.2.2.2", types.StringValue(`the "backup" vpn`)),
+				ruleValue("3.3.3.3", types.StringValue("line one\nline two")),
+			})),
+			expected: "source,description\n" +
+				"1.1.1.1,\"office, floor 2\"\n" +
+				"2.2.2.2,\"the \"\"backup\"\" vpn\"\n" +
+				"3.3.3.3,\"line one\nline two\"\n",
+		},
+		{
+			name: "tuple of objects without descriptions",
+			arg: types.DynamicValue(types.TupleValueMust([]attr.Type{
+				types.ObjectType{AttrTypes: map[string]attr.Type{"source": types.StringType}},
+			}, []attr.Value{
+				types.ObjectValueMust(map[string]attr.Type{"source": types.StringType}, map[string]attr.Value{"source": types.StringValue("1.1.1.1")}),
+			})),
+			expected: "source,description\n1.1.1.1,\n",
+		},
+		{
+			name:     "no rules",
+			arg:      types.DynamicValue(types.ListValueMust(ruleType, []attr.Value{})),
+			expected: "source,description\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := run(t, tt.arg)
+			require.Nil(t, resp.Error)
+			require.Equal(t, types.StringValue(tt.expected), resp.Result.Value())
+		})
+	}
+}
+
+func TestRun_InvalidRules(t *testing.T) {
+	resp := run(t, types.DynamicValue(types.StringValue("1.1.1.1")))
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Error(), "expected a list or set")
+
+	resp = run(t, types.DynamicNull())
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Error(), "must not be null")
+}
@@ -0,0 +1,206 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package validatetrafficfilterfunction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterresource"
+)
+
+var _ function.Function = &Function{}
+
+type Function struct{}
+
+func NewFunction() function.Function {
+	return &Function{}
+}
+
+func (f *Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_traffic_filter"
+}
+
+func (f *Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates a serverless traffic filter definition without calling the API",
+		Description: "Checks an object shaped like the configuration of the `ec_serverless_traffic_filter` resource " +
+			"(`name`, `type`, `region`, `description`, `sources`, `rule_descriptions` and `rule`) and returns a list of problems, " +
+			"which is empty if the definition is valid. No API requests are made.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "traffic_filter",
+				Description: "The traffic filter definition, as an object or map.",
+			},
+		},
+		Return: function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arg types.Dynamic
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &arg))
+	if resp.Error != nil {
+		return
+	}
+
+	if arg.IsNull() || arg.IsUnderlyingValueNull() {
+		resp.Error = function.NewArgumentFuncError(0, "The traffic filter definition must not be null")
+		return
+	}
+
+	value, err := arg.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	spec, err := specFromValue(value)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid traffic filter definition: %s", err))
+		return
+	}
+
+	problems := serverlesstrafficfilterresource.ValidateTrafficFilter(spec)
+	if problems == nil {
+		problems = []string{}
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, problems))
+}
+
+func specFromValue(value tftypes.Value) (serverlesstrafficfilterresource.TrafficFilterSpec, error) {
+	var spec serverlesstrafficfilterresource.TrafficFilterSpec
+
+	attrs, err := mapValue(value)
+	if err != nil {
+		return spec, err
+	}
+
+	for key, target := range map[string]*string{
+		"name":        &spec.Name,
+		"type":        &spec.Type,
+		"region":      &spec.Region,
+		"description": &spec.Description,
+	} {
+		if *target, err = stringValue(attrs[key]); err != nil {
+			return spec, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if spec.Sources, err = stringListValue(attrs["sources"]); err != nil {
+		return spec, fmt.Errorf("sources: %w", err)
+	}
+
+	if descriptions, ok := attrs["rule_descriptions"]; ok && !descriptions.IsNull() {
+		elems, err := mapValue(descriptions)
+		if err != nil {
+			return spec, fmt.Errorf("rule_descriptions: %w", err)
+		}
+		spec.RuleDescriptions = make(map[string]string, len(elems))
+		for source, elem := range elems {
+			if spec.RuleDescriptions[source], err = stringValue(elem); err != nil {
+				return spec, fmt.Errorf("rule_descriptions[%q]: %w", source, err)
+			}
+		}
+	}
+
+	rules, err := listValue(attrs["rule"])
+	if err != nil {
+		return spec, fmt.Errorf("rule: %w", err)
+	}
+	for i, rule := range rules {
+		ruleAttrs, err := mapValue(rule)
+		if err != nil {
+			return spec, fmt.Errorf("rule[%d]: %w", i, err)
+		}
+		var r serverlesstrafficfilterresource.TrafficFilterRuleSpec
+		if r.Source, err = stringValue(ruleAttrs["source"]); err != nil {
+			return spec, fmt.Errorf("rule[%d].source: %w", i, err)
+		}
+		if r.Description, err = stringValue(ruleAttrs["description"]); err != nil {
+			return spec, fmt.Errorf("rule[%d].description: %w", i, err)
+		}
+		spec.Rules = append(spec.Rules, r)
+	}
+
+	return spec, nil
+}
+
+// mapValue returns the attributes of an object, or the elements of a map.
+func mapValue(value tftypes.Value) (map[string]tftypes.Value, error) {
+	var result map[string]tftypes.Value
+	if !value.Type().Is(tftypes.Object{}) && !value.Type().Is(tftypes.Map{}) {
+		return nil, fmt.Errorf("expected an object or map, got %s", value.Type())
+	}
+	if err := value.As(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// listValue returns the elements of a list, set or tuple. Missing and null
+// values are returned as nil.
+func listValue(value tftypes.Value) ([]tftypes.Value, error) {
+	if value.Type() == nil || value.IsNull() {
+		return nil, nil
+	}
+	if !value.Type().Is(tftypes.List{}) && !value.Type().Is(tftypes.Set{}) && !value.Type().Is(tftypes.Tuple{}) {
+		return nil, fmt.Errorf("expected a list or set, got %s", value.Type())
+	}
+	var result []tftypes.Value
+	if err := value.As(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func stringListValue(value tftypes.Value) ([]string, error) {
+	elems, err := listValue(value)
+	if err != nil || elems == nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		s, err := stringValue(elem)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// stringValue returns the value of a string, treating missing and null values
+// as empty strings.
+func stringValue(value tftypes.Value) (string, error) {
+	if value.Type() == nil || value.IsNull() {
+		return "", nil
+	}
+	if !value.Type().Is(tftypes.String) {
+		return "", fmt.Errorf("expected a string, got %s", value.Type())
+	}
+	var result string
+	if err := value.As(&result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package validatetrafficfilterfunction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+var ruleType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"source":      types.StringType,
+	"description": types.StringType,
+}}
+
+func rule(source string) attr.Value {
+	return types.ObjectValueMust(ruleType.AttrTypes, map[string]attr.Value{
+		"source":      types.StringValue(source),
+		"description": types.StringNull(),
+	})
+}
+
+func stringValues(values ...string) []attr.Value {
+	result := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		result = append(result, types.StringValue(v))
+	}
+	return result
+}
+
+func object(attrs map[string]attr.Value) types.Dynamic {
+	attrTypes := make(map[string]attr.Type, len(attrs))
+	for k, v := range attrs {
+		attrTypes[k] = v.Type(context.Background())
+	}
+	return types.DynamicValue(types.ObjectValueMust(attrTypes, attrs))
+}
+
+func run(t *testing.T, arg types.Dynamic) function.RunResponse {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{arg}),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.ListUnknown(types.StringType)),
+	}
+	NewFunction().Run(context.Background(), req, &resp)
+	return resp
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      types.Dynamic
+		expected []string
+	}{
+		{
+			name: "valid rule blocks",
+			arg: object(map[string]attr.Value{
+				"name":   types.StringValue("my-filter"),
+				"type":   types.StringValue("ip"),
+				"region": types.StringValue("us-east-1"),
+				"rule":   types.ListValueMust(ruleType, []attr.Value{rule("1.1.1.1"), rule("10.0.0.0/8")}),
+			}),
+			expected: []string{},
+		},
+		{
+			name: "valid sources given as a map",
+			arg: types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{
+				"name":   types.StringValue("my-filter"),
+				"type":   types.StringValue("vpce"),
+				"region": types.StringValue("us-east-1"),
+			})),
+			expected: []string{"sources: at least one rule is required, defined either by a rule block or by the sources attribute"},
+		},
+		{
+			name: "several problems",
+			arg: object(map[string]attr.Value{
+				"name":              types.StringValue("my-filter"),
+				"type":              types.StringValue("ip"),
+				"region":            types.StringNull(),
+				"sources":           types.SetValueMust(types.StringType, stringValues("1.1.1.1", "vpce-1")),
+				"rule_descriptions": types.MapValueMust(types.StringType, map[string]attr.Value{"2.2.2.2": types.StringValue("office")}),
+			}),
+			expected: []string{
+				"region: must be between 1 and 32 characters long",
+				`sources: "vpce-1" is not a valid IP address or CIDR mask`,
+				`rule_descriptions["2.2.2.2"]: 2.2.2.2 is not part of the sources attribute`,
+			},
+		},
+		{
+			name: "rule blocks and sources",
+			arg: object(map[string]attr.Value{
+				"name":    types.StringValue("my-filter"),
+				"type":    types.StringValue("ip"),
+				"region":  types.StringValue("us-east-1"),
+				"sources": types.ListValueMust(types.StringType, stringValues("1.1.1.1")),
+				"rule":    types.ListValueMust(ruleType, []attr.Value{rule("1.1.1.300")}),
+			}),
+			expected: []string{
+				"sources: rule blocks can't be used together with the sources and rule_descriptions attributes",
+				`rule[0].source: "1.1.1.300" is not a valid IP address or CIDR mask`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := run(t, tt.arg)
+			require.Nil(t, resp.Error)
+
+			expected := types.ListValueMust(types.StringType, stringValues(tt.expected...))
+			require.Equal(t, expected, resp.Result.Value())
+		})
+	}
+}
+
+func TestRun_InvalidDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      types.Dynamic
+		expected string
+	}{
+		{
+			name:     "null",
+			arg:      types.DynamicNull(),
+			expected: "The traffic filter definition must not be null",
+		},
+		{
+			name:     "not an object",
+			arg:      types.DynamicValue(types.StringValue("my-filter")),
+			expected: "Invalid traffic filter definition: expected an object or map, got tftypes.String",
+		},
+		{
+			name: "wrongly typed attribute",
+			arg: object(map[string]attr.Value{
+				"name": types.Int64Value(1),
+			}),
+			expected: "Invalid traffic filter definition: name: expected a string, got tftypes.Number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := run(t, tt.arg)
+			require.Equal(t, function.NewArgumentFuncError(0, tt.expected), resp.Error)
+		})
+	}
+}
@@ -19,7 +19,6 @@ package projectresource
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"time"
 
@@ -146,7 +145,7 @@ func (es elasticsearchApi) Create(ctx context.Context, model resource_elasticsea
 	resp, err := es.client.CreateElasticsearchProjectWithResponse(ctx, createBody)
 	if err != nil {
 		return model, diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -154,10 +153,7 @@ func (es elasticsearchApi) Create(ctx context.Context, model resource_elasticsea
 		return model, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to create elasticsearch_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -207,7 +203,7 @@ func (es elasticsearchApi) Patch(ctx context.Context, model resource_elasticsear
 	resp, err := es.client.PatchElasticsearchProjectWithResponse(ctx, model.Id.ValueString(), nil, updateBody)
 	if err != nil {
 		return diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -215,10 +211,7 @@ func (es elasticsearchApi) Patch(ctx context.Context, model resource_elasticsear
 		return diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to update elasticsearch_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -232,7 +225,7 @@ func (es elasticsearchApi) EnsureInitialised(ctx context.Context, model resource
 		resp, err := es.client.GetElasticsearchProjectStatusWithResponse(ctx, id)
 		if err != nil {
 			return diag.Diagnostics{
-				diag.NewErrorDiagnostic(err.Error(), err.Error()),
+				diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 			}
 		}
 
@@ -240,10 +233,7 @@ func (es elasticsearchApi) EnsureInitialised(ctx context.Context, model resource
 			return diag.Diagnostics{
 				diag.NewErrorDiagnostic(
 					"Failed to get elasticsearch_project status",
-					fmt.Sprintf("The API request failed with: %d %s\n%s",
-						resp.StatusCode(),
-						resp.Status(),
-						resp.Body),
+					util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 				),
 			}
 		}
@@ -260,7 +250,7 @@ func (es elasticsearchApi) Read(ctx context.Context, id string, model resource_e
 	resp, err := es.client.GetElasticsearchProjectWithResponse(ctx, id)
 	if err != nil {
 		return false, model, diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -272,10 +262,7 @@ func (es elasticsearchApi) Read(ctx context.Context, id string, model resource_e
 		return false, model, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to read elasticsearch_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -358,7 +345,7 @@ func (es elasticsearchApi) Delete(ctx context.Context, model resource_elasticsea
 	resp, err := es.client.DeleteElasticsearchProjectWithResponse(ctx, model.Id.ValueString(), nil)
 	if err != nil {
 		return diag.Diagnostics{
-			diag.NewErrorDiagnostic("Failed to delete elasticsearch_project", err.Error()),
+			diag.NewErrorDiagnostic("Failed to delete elasticsearch_project", util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -367,10 +354,7 @@ func (es elasticsearchApi) Delete(ctx context.Context, model resource_elasticsea
 		return diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Request to delete elasticsearch_project failed",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
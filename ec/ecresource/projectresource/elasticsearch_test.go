@@ -19,7 +19,6 @@ package projectresource
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net/http"
 	"testing"
@@ -331,7 +330,7 @@ func TestElasticsearchApi_Create(t *testing.T) {
 				return testData{
 					client: mockApiClient,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -357,10 +356,7 @@ func TestElasticsearchApi_Create(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to create elasticsearch_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -458,6 +454,51 @@ func TestElasticsearchApi_Create(t *testing.T) {
 					nil,
 				)
 
+				return testData{
+					client:        mockApiClient,
+					initialModel:  initialModel,
+					expectedModel: expectedProject,
+				}
+			},
+		},
+		{
+			name: "should set traffic filters in the create request",
+			testData: func(ctx context.Context) testData {
+				initialModel := resource_elasticsearch_project.ElasticsearchProjectModel{
+					Name:           types.StringValue("project name"),
+					RegionId:       types.StringValue("nether region"),
+					TrafficFilters: types.SetValueMust(types.StringType, []attr.Value{types.StringValue("filter-id")}),
+				}
+				createdProject := serverless.ElasticsearchProjectCreated{
+					Id: "created id",
+					Credentials: serverless.ProjectCredentials{
+						Username: "project username",
+						Password: "sekret",
+					},
+				}
+				expectedProject := initialModel
+				expectedProject.Id = types.StringValue(createdProject.Id)
+				expectedProject.Credentials = resource_elasticsearch_project.NewCredentialsValueMust(
+					initialModel.Credentials.AttributeTypes(ctx),
+					map[string]attr.Value{
+						"username": types.StringValue(createdProject.Credentials.Username),
+						"password": types.StringValue(createdProject.Credentials.Password),
+					},
+				)
+
+				// The traffic filters are part of the create request, no patch follows.
+				mockApiClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+				mockApiClient.EXPECT().CreateElasticsearchProjectWithResponse(ctx, serverless.CreateElasticsearchProjectRequest{
+					Name:           initialModel.Name.ValueString(),
+					RegionId:       initialModel.RegionId.ValueString(),
+					TrafficFilters: &serverless.TrafficFilters{{Id: "filter-id"}},
+				}).Return(
+					&serverless.CreateElasticsearchProjectResponse{
+						JSON201: &createdProject,
+					},
+					nil,
+				)
+
 				return testData{
 					client:        mockApiClient,
 					initialModel:  initialModel,
@@ -517,7 +558,7 @@ func TestElasticsearchApi_Patch(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -550,10 +591,7 @@ func TestElasticsearchApi_Patch(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to update elasticsearch_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -682,7 +720,7 @@ func TestElasticsearchApi_EnsureInitialised(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -724,10 +762,7 @@ func TestElasticsearchApi_EnsureInitialised(t *testing.T) {
 
 						diag.NewErrorDiagnostic(
 							"Failed to get elasticsearch_project status",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -813,7 +848,7 @@ func TestElasticsearchApi_Read(t *testing.T) {
 					initialModel:  initialModel,
 					expectedModel: initialModel,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -871,10 +906,7 @@ func TestElasticsearchApi_Read(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to read elasticsearch_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -1087,7 +1119,7 @@ func TestElasticsearchApi_Delete(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic("Failed to delete elasticsearch_project", assert.AnError.Error()),
+						diag.NewErrorDiagnostic("Failed to delete elasticsearch_project", util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -1119,10 +1151,7 @@ func TestElasticsearchApi_Delete(t *testing.T) {
 
 						diag.NewErrorDiagnostic(
 							"Request to delete elasticsearch_project failed",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -19,7 +19,6 @@ package projectresource
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"time"
 
@@ -121,7 +120,7 @@ func (obs observabilityApi) Create(ctx context.Context, model resource_observabi
 	resp, err := obs.client.CreateObservabilityProjectWithResponse(ctx, createBody)
 	if err != nil {
 		return model, diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -129,10 +128,7 @@ func (obs observabilityApi) Create(ctx context.Context, model resource_observabi
 		return model, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to create observability_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -173,7 +169,7 @@ func (obs observabilityApi) Patch(ctx context.Context, model resource_observabil
 	resp, err := obs.client.PatchObservabilityProjectWithResponse(ctx, model.Id.ValueString(), nil, updateBody)
 	if err != nil {
 		return diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -181,10 +177,7 @@ func (obs observabilityApi) Patch(ctx context.Context, model resource_observabil
 		return diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to update observability_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -198,7 +191,7 @@ func (obs observabilityApi) EnsureInitialised(ctx context.Context, model resourc
 		resp, err := obs.client.GetObservabilityProjectStatusWithResponse(ctx, id)
 		if err != nil {
 			return diag.Diagnostics{
-				diag.NewErrorDiagnostic(err.Error(), err.Error()),
+				diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 			}
 		}
 
@@ -206,10 +199,7 @@ func (obs observabilityApi) EnsureInitialised(ctx context.Context, model resourc
 			return diag.Diagnostics{
 				diag.NewErrorDiagnostic(
 					"Failed to get observability_project status",
-					fmt.Sprintf("The API request failed with: %d %s\n%s",
-						resp.StatusCode(),
-						resp.Status(),
-						resp.Body),
+					util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 				),
 			}
 		}
@@ -226,7 +216,7 @@ func (obs observabilityApi) Read(ctx context.Context, id string, model resource_
 	resp, err := obs.client.GetObservabilityProjectWithResponse(ctx, id)
 	if err != nil {
 		return false, model, diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -238,10 +228,7 @@ func (obs observabilityApi) Read(ctx context.Context, id string, model resource_
 		return false, model, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to read observability_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -302,7 +289,7 @@ func (obs observabilityApi) Delete(ctx context.Context, model resource_observabi
 	resp, err := obs.client.DeleteObservabilityProjectWithResponse(ctx, model.Id.ValueString(), nil)
 	if err != nil {
 		return diag.Diagnostics{
-			diag.NewErrorDiagnostic("Failed to delete observability_project", err.Error()),
+			diag.NewErrorDiagnostic("Failed to delete observability_project", util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -311,10 +298,7 @@ func (obs observabilityApi) Delete(ctx context.Context, model resource_observabi
 		return diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Request to delete observability_project failed",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
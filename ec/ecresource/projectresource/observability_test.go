@@ -19,7 +19,6 @@ package projectresource
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net/http"
 	"testing"
@@ -333,7 +332,7 @@ func TestObservabilityApi_Create(t *testing.T) {
 				return testData{
 					client: mockApiClient,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -359,10 +358,7 @@ func TestObservabilityApi_Create(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to create observability_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -506,7 +502,7 @@ func TestObservabilityApi_Patch(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -539,10 +535,7 @@ func TestObservabilityApi_Patch(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to update observability_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -655,7 +648,7 @@ func TestObservabilityApi_EnsureInitialised(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -697,10 +690,7 @@ func TestObservabilityApi_EnsureInitialised(t *testing.T) {
 
 						diag.NewErrorDiagnostic(
 							"Failed to get observability_project status",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -786,7 +776,7 @@ func TestObservabilityApi_Read(t *testing.T) {
 					initialModel:  initialModel,
 					expectedModel: initialModel,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -844,10 +834,7 @@ func TestObservabilityApi_Read(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to read observability_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -1044,7 +1031,7 @@ func TestObservabilityApi_Delete(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic("Failed to delete observability_project", assert.AnError.Error()),
+						diag.NewErrorDiagnostic("Failed to delete observability_project", util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -1076,10 +1063,7 @@ func TestObservabilityApi_Delete(t *testing.T) {
 
 						diag.NewErrorDiagnostic(
 							"Request to delete observability_project failed",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
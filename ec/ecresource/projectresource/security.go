@@ -19,7 +19,6 @@ package projectresource
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"time"
 
@@ -138,7 +137,7 @@ func (sec securityApi) Create(ctx context.Context, model resource_security_proje
 	resp, err := sec.client.CreateSecurityProjectWithResponse(ctx, createBody)
 	if err != nil {
 		return model, diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -146,10 +145,7 @@ func (sec securityApi) Create(ctx context.Context, model resource_security_proje
 		return model, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to create security_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -185,7 +181,7 @@ func (sec securityApi) Patch(ctx context.Context, model resource_security_projec
 	resp, err := sec.client.PatchSecurityProjectWithResponse(ctx, model.Id.ValueString(), nil, updateBody)
 	if err != nil {
 		return diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -193,10 +189,7 @@ func (sec securityApi) Patch(ctx context.Context, model resource_security_projec
 		return diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to update security_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -210,7 +203,7 @@ func (sec securityApi) EnsureInitialised(ctx context.Context, model resource_sec
 		resp, err := sec.client.GetSecurityProjectStatusWithResponse(ctx, id)
 		if err != nil {
 			return diag.Diagnostics{
-				diag.NewErrorDiagnostic(err.Error(), err.Error()),
+				diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 			}
 		}
 
@@ -218,10 +211,7 @@ func (sec securityApi) EnsureInitialised(ctx context.Context, model resource_sec
 			return diag.Diagnostics{
 				diag.NewErrorDiagnostic(
 					"Failed to get security_project status",
-					fmt.Sprintf("The API request failed with: %d %s\n%s",
-						resp.StatusCode(),
-						resp.Status(),
-						resp.Body),
+					util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 				),
 			}
 		}
@@ -238,7 +228,7 @@ func (sec securityApi) Read(ctx context.Context, id string, model resource_secur
 	resp, err := sec.client.GetSecurityProjectWithResponse(ctx, id)
 	if err != nil {
 		return false, model, diag.Diagnostics{
-			diag.NewErrorDiagnostic(err.Error(), err.Error()),
+			diag.NewErrorDiagnostic(err.Error(), util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -250,10 +240,7 @@ func (sec securityApi) Read(ctx context.Context, id string, model resource_secur
 		return false, model, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Failed to read security_project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
@@ -313,7 +300,7 @@ func (sec securityApi) Delete(ctx context.Context, model resource_security_proje
 	resp, err := sec.client.DeleteSecurityProjectWithResponse(ctx, model.Id.ValueString(), nil)
 	if err != nil {
 		return diag.Diagnostics{
-			diag.NewErrorDiagnostic("Failed to delete security_project", err.Error()),
+			diag.NewErrorDiagnostic("Failed to delete security_project", util.RequestErrorDetail(err)),
 		}
 	}
 
@@ -322,10 +309,7 @@ func (sec securityApi) Delete(ctx context.Context, model resource_security_proje
 		return diag.Diagnostics{
 			diag.NewErrorDiagnostic(
 				"Request to delete security_project failed",
-				fmt.Sprintf("The API request failed with: %d %s\n%s",
-					resp.StatusCode(),
-					resp.Status(),
-					resp.Body),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body),
 			),
 		}
 	}
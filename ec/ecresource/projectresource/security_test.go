@@ -19,7 +19,6 @@ package projectresource
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net/http"
 	"testing"
@@ -336,7 +335,7 @@ func TestSecurityApi_Create(t *testing.T) {
 				return testData{
 					client: mockApiClient,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -362,10 +361,7 @@ func TestSecurityApi_Create(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to create security_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -509,7 +505,7 @@ func TestSecurityApi_Patch(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -542,10 +538,7 @@ func TestSecurityApi_Patch(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to update security_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -658,7 +651,7 @@ func TestSecurityApi_EnsureInitialised(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -700,10 +693,7 @@ func TestSecurityApi_EnsureInitialised(t *testing.T) {
 
 						diag.NewErrorDiagnostic(
 							"Failed to get security_project status",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -789,7 +779,7 @@ func TestSecurityApi_Read(t *testing.T) {
 					initialModel:  initialModel,
 					expectedModel: initialModel,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic(assert.AnError.Error(), assert.AnError.Error()),
+						diag.NewErrorDiagnostic(assert.AnError.Error(), util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -847,10 +837,7 @@ func TestSecurityApi_Read(t *testing.T) {
 					expectedDiags: diag.Diagnostics{
 						diag.NewErrorDiagnostic(
 							"Failed to read security_project",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -1045,7 +1032,7 @@ func TestSecurityApi_Delete(t *testing.T) {
 					client: mockApiClient,
 					model:  model,
 					expectedDiags: diag.Diagnostics{
-						diag.NewErrorDiagnostic("Failed to delete security_project", assert.AnError.Error()),
+						diag.NewErrorDiagnostic("Failed to delete security_project", util.RequestErrorDetail(assert.AnError)),
 					},
 				}
 			},
@@ -1077,10 +1064,7 @@ func TestSecurityApi_Delete(t *testing.T) {
 
 						diag.NewErrorDiagnostic(
 							"Request to delete security_project failed",
-							fmt.Sprintf("The API request failed with: %d %s\n%s",
-								failedResponse.StatusCode(),
-								failedResponse.Status(),
-								failedResponse.Body),
+							util.APIFailureDetail(failedResponse.HTTPResponse, failedResponse.Body),
 						),
 					},
 				}
@@ -19,6 +19,7 @@ package projectresource
 
 import (
 	"context"
+	"sort"
 
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -48,16 +49,23 @@ func trafficFiltersFromModel(ctx context.Context, tfSet types.Set) (*serverless.
 	return &filters, nil
 }
 
-// trafficFiltersToModel converts API traffic filters to a Terraform Set of strings
+// trafficFiltersToModel converts API traffic filters to a Terraform Set of strings.
+// The IDs are deduplicated and sorted, so the resulting set doesn't depend on the order returned by the API.
 func trafficFiltersToModel(ctx context.Context, filters *serverless.TrafficFilters) (types.Set, diag.Diagnostics) {
 	if filters == nil || len(*filters) == 0 {
 		return types.SetNull(types.StringType), nil
 	}
 
+	seen := make(map[string]bool, len(*filters))
 	ids := make([]string, 0, len(*filters))
 	for _, f := range *filters {
+		if seen[f.Id] {
+			continue
+		}
+		seen[f.Id] = true
 		ids = append(ids, f.Id)
 	}
+	sort.Strings(ids)
 
 	return types.SetValueFrom(ctx, types.StringType, ids)
 }
@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package projectresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrafficFiltersToModel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return a null set for nil filters", func(t *testing.T) {
+		set, diags := trafficFiltersToModel(ctx, nil)
+		require.False(t, diags.HasError())
+		require.True(t, set.IsNull())
+	})
+
+	t.Run("should dedupe and sort the filter IDs", func(t *testing.T) {
+		filters := serverless.TrafficFilters{{Id: "c"}, {Id: "a"}, {Id: "b"}, {Id: "a"}, {Id: "c"}}
+
+		set, diags := trafficFiltersToModel(ctx, &filters)
+		require.False(t, diags.HasError())
+
+		expected := types.SetValueMust(types.StringType, []attr.Value{
+			types.StringValue("a"),
+			types.StringValue("b"),
+			types.StringValue("c"),
+		})
+		require.Equal(t, expected, set)
+		require.Equal(t, []attr.Value{
+			types.StringValue("a"),
+			types.StringValue("b"),
+			types.StringValue("c"),
+		}, set.Elements())
+	})
+
+	t.Run("should produce the same set regardless of API order", func(t *testing.T) {
+		first := serverless.TrafficFilters{{Id: "b"}, {Id: "a"}}
+		second := serverless.TrafficFilters{{Id: "a"}, {Id: "b"}, {Id: "b"}}
+
+		firstSet, diags := trafficFiltersToModel(ctx, &first)
+		require.False(t, diags.HasError())
+		secondSet, diags := trafficFiltersToModel(ctx, &second)
+		require.False(t, diags.HasError())
+
+		require.True(t, firstSet.Equal(secondSet))
+		require.Equal(t, firstSet.Elements(), secondSet.Elements())
+	})
+}
@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterassocresource
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+)
+
+// projectCacheTTL is how long a project read is reused. The provider process only lives for a
+// single Terraform operation, so this merely spares redundant reads of the same project when
+// several of its associations are handled in one apply.
+const projectCacheTTL = 30 * time.Second
+
+// sharedProjectCache is used by all association resources of the provider process.
+var sharedProjectCache = newProjectCache(projectCacheTTL)
+
+type projectKey struct {
+	projectType string
+	projectID   string
+}
+
+type cachedProject struct {
+	project serverlessutil.Project
+	readAt  time.Time
+}
+
+// projectCache holds recently read projects, and a lock per project which is held while an
+// association reads or updates the project. A nil cache caches nothing and locks nothing.
+type projectCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	locks    map[projectKey]*sync.Mutex
+	projects map[projectKey]cachedProject
+}
+
+func newProjectCache(ttl time.Duration) *projectCache {
+	return &projectCache{
+		ttl:      ttl,
+		now:      time.Now,
+		locks:    map[projectKey]*sync.Mutex{},
+		projects: map[projectKey]cachedProject{},
+	}
+}
+
+// lock acquires the lock of the given project and returns the function releasing it.
+func (c *projectCache) lock(projectType, projectID string) func() {
+	if c == nil {
+		return func() {}
+	}
+
+	key := projectKey{projectType: projectType, projectID: projectID}
+	c.mu.Lock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[key] = l
+	}
+	c.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+func (c *projectCache) get(projectType, projectID string) (serverlessutil.Project, bool) {
+	if c == nil {
+		return serverlessutil.Project{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := projectKey{projectType: projectType, projectID: projectID}
+	cached, ok := c.projects[key]
+	if !ok || c.now().Sub(cached.readAt) >= c.ttl {
+		delete(c.projects, key)
+		return serverlessutil.Project{}, false
+	}
+
+	project := cached.project
+	project.TrafficFilters = append([]serverless.TrafficFilter{}, cached.project.TrafficFilters...)
+	return project, true
+}
+
+func (c *projectCache) put(projectType, projectID string, project serverlessutil.Project) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	project.TrafficFilters = append([]serverless.TrafficFilter{}, project.TrafficFilters...)
+	c.projects[projectKey{projectType: projectType, projectID: projectID}] = cachedProject{project: project, readAt: c.now()}
+}
+
+func (c *projectCache) invalidate(projectType, projectID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.projects, projectKey{projectType: projectType, projectID: projectID})
+}
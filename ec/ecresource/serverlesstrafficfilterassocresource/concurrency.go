@@ -0,0 +1,183 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterassocresource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+// maxConflictRetries limits how often a project update is retried after a concurrent modification.
+const maxConflictRetries = 3
+
+const (
+	// notReadyPollInterval is the time between attempts to update a project which isn't ready yet.
+	notReadyPollInterval = 10 * time.Second
+	// defaultNotReadyTimeout limits how long to wait for a project to become ready, unless the
+	// timeout of the operation is configured.
+	defaultNotReadyTimeout = 10 * time.Minute
+)
+
+const (
+	// missingAssociationBackoff is the time before reading a project which doesn't list an
+	// association again, it's doubled with every further read. A patch made elsewhere may not
+	// be visible right away.
+	missingAssociationBackoff = 2 * time.Second
+	// defaultMissingAssociationTimeout limits how long to wait for a project to list an
+	// association before concluding it's gone, unless the read timeout is configured.
+	defaultMissingAssociationTimeout = 6 * time.Second
+)
+
+type patchOutcome int
+
+const (
+	patched patchOutcome = iota
+	patchConflict
+	patchNotReady
+)
+
+// updateProjectTrafficFilters applies update to the traffic filters of the given project and patches it.
+// The patch is conditional on the project's ETag, so that changes made by others between reading and
+// patching the project aren't lost. On a conflict, the project is read again and the update is retried.
+// Projects which are still being provisioned reject updates, these are retried until the project is ready
+// or the timeout is reached.
+func (r *Resource) updateProjectTrafficFilters(
+	ctx context.Context,
+	projectID, projectType string,
+	project serverlessutil.Project,
+	timeout time.Duration,
+	update func(current []serverless.TrafficFilter) ([]serverless.TrafficFilter, bool),
+) diag.Diagnostics {
+	conflicts := 0
+	var waited time.Duration
+	for {
+		filters, changed := update(project.TrafficFilters)
+		if !changed {
+			return nil
+		}
+
+		outcome, diags := r.patchProjectTrafficFilters(ctx, projectID, projectType, filters, project.ETag)
+		if diags.HasError() {
+			return diags
+		}
+
+		switch outcome {
+		case patched:
+			return diags
+		case patchNotReady:
+			if waited >= timeout {
+				diags.AddError(
+					"Project not ready",
+					fmt.Sprintf("The %s project %s is still in a transitional state, such as being provisioned, after waiting %s for it to become ready. Its traffic filters can't be updated until then, please retry the operation later.", projectType, projectID, waited),
+				)
+				return diags
+			}
+			if err := r.wait(ctx, notReadyPollInterval); err != nil {
+				diags.AddError(
+					"Project not ready",
+					fmt.Sprintf("Stopped waiting for the %s project %s to become ready: %s", projectType, projectID, err),
+				)
+				return diags
+			}
+			waited += notReadyPollInterval
+		case patchConflict:
+			if conflicts >= maxConflictRetries {
+				diags.AddError(
+					util.APIUpdateFailed,
+					util.OperationDetail("update project", fmt.Sprintf("The traffic filters of %s project %s were modified concurrently %d times in a row, please retry the operation.", projectType, projectID, conflicts+1)),
+				)
+				return diags
+			}
+			conflicts++
+		}
+
+		project, diags = r.getProject(ctx, projectID, projectType)
+		if diags.HasError() {
+			return diags
+		}
+	}
+}
+
+// awaitAssociation reads the project again, with a growing backoff, until it lists the traffic
+// filter or the timeout is reached. It returns the last read of the project, and whether the
+// traffic filter was found.
+func (r *Resource) awaitAssociation(ctx context.Context, projectID, projectType, trafficFilterID string, timeout time.Duration) (serverlessutil.Project, bool, diag.Diagnostics) {
+	var project serverlessutil.Project
+	var diags diag.Diagnostics
+	for waited, backoff := time.Duration(0), missingAssociationBackoff; waited < timeout; waited, backoff = waited+backoff, 2*backoff {
+		backoff = min(backoff, timeout-waited)
+		if err := r.wait(ctx, backoff); err != nil {
+			diags.AddError(
+				util.APIReadFailed,
+				util.OperationDetail("read project", fmt.Sprintf("Stopped waiting for the %s project %s to list traffic filter %s: %s", projectType, projectID, trafficFilterID, err)),
+			)
+			return project, false, diags
+		}
+		project, diags = serverlessutil.FetchProject(ctx, r.client, projectID, projectType)
+		if diags.HasError() {
+			return project, false, diags
+		}
+		r.projects.put(projectType, projectID, project)
+		if hasTrafficFilter(project.TrafficFilters, trafficFilterID) {
+			return project, true, diags
+		}
+	}
+	return project, false, diags
+}
+
+func hasTrafficFilter(filters []serverless.TrafficFilter, trafficFilterID string) bool {
+	for _, f := range filters {
+		if f.Id == trafficFilterID {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictOutcome tells apart the failed updates which are worth retrying: a 409 mentioning that
+// the project isn't ready is returned while it's being provisioned, other 409s and 412s are
+// concurrent modifications.
+func conflictOutcome(statusCode int, body []byte) patchOutcome {
+	switch {
+	case statusCode == http.StatusConflict && bytes.Contains(bytes.ToLower(body), []byte("not ready")):
+		return patchNotReady
+	case statusCode == http.StatusPreconditionFailed || statusCode == http.StatusConflict:
+		return patchConflict
+	default:
+		return patched
+	}
+}
+
+// wait waits for the given duration, unless the context is done before.
+func (r *Resource) wait(ctx context.Context, d time.Duration) error {
+	if r.sleep == nil {
+		return timeouts.Sleep(ctx, d)
+	}
+	r.sleep(d)
+	return ctx.Err()
+}
@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterassocresource
+
+import (
+	"strings"
+)
+
+// associationIDSeparator separates the project and traffic filter IDs of an association ID. Unlike
+// the hyphen used by earlier versions of the provider, neither ID can contain it. It's also the
+// separator of import IDs, so that association IDs can be imported as is.
+const associationIDSeparator = ","
+
+// legacyAssociationIDSeparator is the separator used by earlier versions of the provider.
+const legacyAssociationIDSeparator = "-"
+
+// AssociationID returns the canonical ID of the association between a project and a traffic filter
+func AssociationID(projectID, trafficFilterID string) string {
+	return projectID + associationIDSeparator + trafficFilterID
+}
+
+// parseAssociationID splits an association ID into the project and traffic filter IDs. IDs using
+// the legacy hyphen separator are ambiguous if the project or traffic filter ID contains hyphens
+// as well, they're only split if the traffic filter ID is known or if there's a single hyphen.
+func parseAssociationID(id, knownTrafficFilterID string) (projectID, trafficFilterID string, ok bool) {
+	if projectID, trafficFilterID, found := strings.Cut(id, associationIDSeparator); found {
+		return projectID, trafficFilterID, projectID != "" && trafficFilterID != "" && !strings.Contains(trafficFilterID, associationIDSeparator)
+	}
+
+	if knownTrafficFilterID != "" {
+		projectID, found := strings.CutSuffix(id, legacyAssociationIDSeparator+knownTrafficFilterID)
+		return projectID, knownTrafficFilterID, found && projectID != ""
+	}
+
+	if strings.Count(id, legacyAssociationIDSeparator) != 1 {
+		return "", "", false
+	}
+	projectID, trafficFilterID, _ = strings.Cut(id, legacyAssociationIDSeparator)
+	return projectID, trafficFilterID, projectID != "" && trafficFilterID != ""
+}
@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterassocresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssociationID_RoundTrip(t *testing.T) {
+	tests := []struct {
+		projectID       string
+		trafficFilterID string
+	}{
+		{projectID: "project", trafficFilterID: "filter"},
+		{projectID: "my-project-id", trafficFilterID: "filter"},
+		{projectID: "my-project-id", trafficFilterID: "my-filter-id"},
+		{projectID: "3f2b4c6d8e0a1b2c3d4e5f6a7b8c9d0e", trafficFilterID: "a1b2c3d4-e5f6-a7b8-c9d0-e1f2a3b4c5d6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.projectID+" "+tt.trafficFilterID, func(t *testing.T) {
+			projectID, trafficFilterID, ok := parseAssociationID(AssociationID(tt.projectID, tt.trafficFilterID), "")
+			require.True(t, ok)
+			require.Equal(t, tt.projectID, projectID)
+			require.Equal(t, tt.trafficFilterID, trafficFilterID)
+		})
+	}
+}
+
+func TestParseAssociationID(t *testing.T) {
+	tests := []struct {
+		name                    string
+		id                      string
+		knownTrafficFilterID    string
+		expectedProjectID       string
+		expectedTrafficFilterID string
+		expectedOK              bool
+	}{
+		{
+			name:                    "legacy ID with a single hyphen",
+			id:                      "project-filter",
+			expectedProjectID:       "project",
+			expectedTrafficFilterID: "filter",
+			expectedOK:              true,
+		},
+		{
+			name:                    "legacy ID with hyphens and a known traffic filter",
+			id:                      "my-project-id-my-filter-id",
+			knownTrafficFilterID:    "my-filter-id",
+			expectedProjectID:       "my-project-id",
+			expectedTrafficFilterID: "my-filter-id",
+			expectedOK:              true,
+		},
+		{
+			name: "ambiguous legacy ID",
+			id:   "my-project-id-my-filter-id",
+		},
+		{
+			name:                 "legacy ID not ending with the known traffic filter",
+			id:                   "project-filter",
+			knownTrafficFilterID: "other",
+		},
+		{
+			name: "too many parts",
+			id:   "project,security,filter",
+		},
+		{
+			name: "missing project ID",
+			id:   ",filter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectID, trafficFilterID, ok := parseAssociationID(tt.id, tt.knownTrafficFilterID)
+			require.Equal(t, tt.expectedOK, ok)
+			if tt.expectedOK {
+				require.Equal(t, tt.expectedProjectID, projectID)
+				require.Equal(t, tt.expectedTrafficFilterID, trafficFilterID)
+			}
+		})
+	}
+}
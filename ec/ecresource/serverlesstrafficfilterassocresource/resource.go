@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -30,6 +31,10 @@ import (
 
 	"github.com/elastic/terraform-provider-ec/ec/internal"
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+	"github.com/elastic/terraform-provider-ec/ec/internal/transport"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
 )
 
 var _ resource.Resource = &Resource{}
@@ -37,7 +42,13 @@ var _ resource.ResourceWithConfigure = &Resource{}
 var _ resource.ResourceWithImportState = &Resource{}
 
 type Resource struct {
-	client serverless.ClientWithResponsesInterface
+	client             serverless.ClientWithResponsesInterface
+	projects           *projectCache
+	filterLists        *serverlessutil.FilterListCache
+	mutationWindow     *util.MutationWindow
+	diagnosticsJSONLog bool
+	// sleep waits between polls of projects which aren't ready, timeouts.Sleep if nil.
+	sleep func(time.Duration)
 }
 
 func NewResource() resource.Resource {
@@ -51,7 +62,23 @@ func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, res
 func (r *Resource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	clients, diags := internal.ConvertProviderData(req.ProviderData)
 	resp.Diagnostics.Append(diags...)
+	// Leave the resource unconfigured rather than partially configured.
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	r.client = clients.Serverless
+	r.projects = sharedProjectCache
+	r.filterLists = clients.FilterLists
+	r.mutationWindow = clients.MutationWindow
+	r.diagnosticsJSONLog = clients.DiagnosticsJSONLog
+}
+
+// logDiagnostics logs the diagnostics of an operation as JSON lines, if enabled
+// by the diagnostics_json_log provider attribute.
+func (r *Resource) logDiagnostics(ctx context.Context, diags diag.Diagnostics, failures *transport.Failures) {
+	if r.diagnosticsJSONLog {
+		transport.LogDiagnosticsJSON(ctx, diags, failures)
+	}
 }
 
 func resourceReady(r *Resource, dg *diag.Diagnostics) bool {
@@ -66,6 +93,16 @@ func resourceReady(r *Resource, dg *diag.Diagnostics) bool {
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "create traffic filter association")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !resourceReady(r, &resp.Diagnostics) {
 		return
 	}
@@ -79,40 +116,85 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 
 	projectID := model.ProjectID.ValueString()
 	projectType := model.ProjectType.ValueString()
-	trafficFilterID := model.TrafficFilterID.ValueString()
 
-	// Get current traffic filters from the project
-	currentFilters, diags := r.getProjectTrafficFilters(ctx, projectID, projectType)
+	// Serialize the handling of associations with the same project
+	defer r.projects.lock(projectType, projectID)()
+
+	project, diags := r.getProject(ctx, projectID, projectType)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	// Check if filter is already associated
-	for _, f := range currentFilters {
-		if f.Id == trafficFilterID {
-			// Already associated, just set state
-			model.ID = types.StringValue(fmt.Sprintf("%s-%s", projectID, trafficFilterID))
-			resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+	model.ProjectName = types.StringValue(project.Name)
+	model.CloudProvider = serverlessutil.CloudProvider(project.RegionID)
+
+	// Resolve the filter by name in the project's region if no ID is given
+	if name := model.TrafficFilterName.ValueString(); name != "" {
+		id, diags := serverlessutil.ResolveTrafficFilterName(ctx, r.client, r.filterLists, name, project.RegionID)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		model.TrafficFilterID = types.StringValue(id)
+	} else {
+		// Patching a project with an unknown filter may silently do nothing, so fail early
+		exists, diags := r.trafficFilterExists(ctx, model.TrafficFilterID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !exists {
+			resp.Diagnostics.AddError(
+				"Traffic filter not found",
+				fmt.Sprintf("Traffic filter %s does not exist and can't be associated with project %s", model.TrafficFilterID.ValueString(), projectID),
+			)
 			return
 		}
 	}
+	trafficFilterID := model.TrafficFilterID.ValueString()
 
-	// Add the new filter
-	newFilters := append(currentFilters, serverless.TrafficFilter{Id: trafficFilterID})
+	timeout, diags := timeouts.Create(model.Timeouts, defaultNotReadyTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Patch the project with updated filters
-	diags = r.patchProjectTrafficFilters(ctx, projectID, projectType, newFilters)
+	// Add the new filter, unless it's already associated
+	alreadyAssociated := false
+	diags = r.updateProjectTrafficFilters(ctx, projectID, projectType, project, timeout, func(current []serverless.TrafficFilter) ([]serverless.TrafficFilter, bool) {
+		if hasTrafficFilter(current, trafficFilterID) {
+			alreadyAssociated = true
+			return current, false
+		}
+		newFilters := make([]serverless.TrafficFilter, 0, len(current)+1)
+		newFilters = append(newFilters, current...)
+		return append(newFilters, serverless.TrafficFilter{Id: trafficFilterID}), true
+	})
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	model.ID = types.StringValue(fmt.Sprintf("%s-%s", projectID, trafficFilterID))
+	if alreadyAssociated {
+		resp.Diagnostics.AddWarning(
+			"Traffic filter already associated",
+			fmt.Sprintf("Traffic filter %s was already associated with %s project %s, so another resource or actor may already manage this association. "+
+				"Destroying either of them removes the association for both. Make sure only one ec_serverless_traffic_filter_association resource manages it, "+
+				"and that it isn't also managed through the project's traffic_filters attribute.", trafficFilterID, projectType, projectID),
+		)
+	}
+
+	model.ID = types.StringValue(AssociationID(projectID, trafficFilterID))
+	model.KeepDefaultFilterOnDestroy = keepDefaultFilterOnDestroy(model)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
 	if !resourceReady(r, &resp.Diagnostics) {
 		return
 	}
@@ -128,40 +210,84 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	projectType := model.ProjectType.ValueString()
 	trafficFilterID := model.TrafficFilterID.ValueString()
 
-	// Get current traffic filters from the project
-	currentFilters, diags := r.getProjectTrafficFilters(ctx, projectID, projectType)
+	// The association can't exist anymore if the filter itself has been deleted
+	exists, diags := r.trafficFilterExists(ctx, trafficFilterID)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	// Check if the association still exists
-	found := false
-	for _, f := range currentFilters {
-		if f.Id == trafficFilterID {
-			found = true
-			break
-		}
+	if !exists {
+		resp.Diagnostics.AddWarning(
+			"Traffic filter not found",
+			fmt.Sprintf("Traffic filter %s has been deleted outside of Terraform, removing its association with project %s from the state", trafficFilterID, projectID),
+		)
+		resp.State.RemoveResource(ctx)
+		return
 	}
 
-	if !found {
-		// Association no longer exists
-		resp.State.RemoveResource(ctx)
+	// Serialize the handling of associations with the same project
+	defer r.projects.lock(projectType, projectID)()
+
+	project, diags := r.getProject(ctx, projectID, projectType)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Check if the association still exists, giving a recent patch time to become visible
+	if !hasTrafficFilter(project.TrafficFilters, trafficFilterID) {
+		timeout, diags := timeouts.Read(model.Timeouts, defaultMissingAssociationTimeout)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var found bool
+		project, found, diags = r.awaitAssociation(ctx, projectID, projectType, trafficFilterID, timeout)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !found {
+			// Association no longer exists
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	}
+	model.ProjectName = types.StringValue(project.Name)
+	model.CloudProvider = serverlessutil.CloudProvider(project.RegionID)
+
+	// Migrates IDs of associations created by earlier versions of the provider.
+	model.ID = types.StringValue(AssociationID(projectID, trafficFilterID))
+	model.KeepDefaultFilterOnDestroy = keepDefaultFilterOnDestroy(model)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// All attributes require replacement, so Update should never be called
-	resp.Diagnostics.AddError(
-		"Update not supported",
-		"All attributes of this resource require replacement. This is a bug in the provider.",
-	)
+	var plan, state modelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All other attributes require replacement, and keep_default_filter_on_destroy
+	// only matters when destroying, so there is nothing to update in the project.
+	state.KeepDefaultFilterOnDestroy = plan.KeepDefaultFilterOnDestroy
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "delete traffic filter association")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !resourceReady(r, &resp.Diagnostics) {
 		return
 	}
@@ -177,51 +303,100 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 	projectType := model.ProjectType.ValueString()
 	trafficFilterID := model.TrafficFilterID.ValueString()
 
-	// Get current traffic filters from the project
-	currentFilters, diags := r.getProjectTrafficFilters(ctx, projectID, projectType)
+	if model.KeepDefaultFilterOnDestroy.ValueBool() {
+		filter, diags := r.getTrafficFilter(ctx, trafficFilterID)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if filter != nil && filter.IncludeByDefault {
+			resp.Diagnostics.AddWarning(
+				"Traffic filter association kept",
+				fmt.Sprintf("Traffic filter %s is included by default in new projects, so it's managed by the platform and kept associated with %s project %s. "+
+					"Set keep_default_filter_on_destroy to false before destroying the association to remove it from the project.", trafficFilterID, projectType, projectID),
+			)
+			return
+		}
+	}
+
+	// Serialize the handling of associations with the same project
+	defer r.projects.lock(projectType, projectID)()
+
+	project, diags := r.getProject(ctx, projectID, projectType)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Remove the filter from the list
-	newFilters := make([]serverless.TrafficFilter, 0, len(currentFilters))
-	for _, f := range currentFilters {
-		if f.Id != trafficFilterID {
-			newFilters = append(newFilters, f)
-		}
+	timeout, diags := timeouts.Delete(model.Timeouts, defaultNotReadyTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Patch the project with updated filters
-	diags = r.patchProjectTrafficFilters(ctx, projectID, projectType, newFilters)
+	// Remove the filter from the list
+	diags = r.updateProjectTrafficFilters(ctx, projectID, projectType, project, timeout, func(current []serverless.TrafficFilter) ([]serverless.TrafficFilter, bool) {
+		newFilters := make([]serverless.TrafficFilter, 0, len(current))
+		for _, f := range current {
+			if f.Id != trafficFilterID {
+				newFilters = append(newFilters, f)
+			}
+		}
+		return newFilters, len(newFilters) != len(current)
+	})
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Expected format: project_id,project_type,traffic_filter_id
-	parts := strings.Split(req.ID, ",")
-	if len(parts) != 3 {
+	// Expected format: project_id,project_type,traffic_filter_id or project_id,traffic_filter_id
+	// Import IDs are often pasted with stray whitespace.
+	parts := strings.Split(strings.TrimSpace(req.ID), associationIDSeparator)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	// IDs of associations created by earlier versions of the provider are accepted as well.
+	if len(parts) == 1 {
+		if projectID, trafficFilterID, ok := parseAssociationID(parts[0], ""); ok {
+			parts = []string{projectID, trafficFilterID}
+		}
+	}
+	if len(parts) != 2 && len(parts) != 3 {
 		resp.Diagnostics.AddError(
 			"Invalid import ID",
-			fmt.Sprintf("Expected format: project_id,project_type,traffic_filter_id. Got: %s", req.ID),
+			fmt.Sprintf("Expected format: project_id,project_type,traffic_filter_id or project_id,traffic_filter_id. Got: %s", req.ID),
 		)
 		return
 	}
 
 	projectID := parts[0]
-	projectType := parts[1]
-	trafficFilterID := parts[2]
+	trafficFilterID := parts[len(parts)-1]
 
-	// Validate project type
-	if projectType != "elasticsearch" && projectType != "observability" && projectType != "security" {
-		resp.Diagnostics.AddError(
-			"Invalid project type",
-			fmt.Sprintf("project_type must be one of: elasticsearch, observability, security. Got: %s", projectType),
-		)
-		return
+	var projectType string
+	if len(parts) == 3 {
+		projectType = parts[1]
+
+		// Validate project type
+		if projectType != "elasticsearch" && projectType != "observability" && projectType != "security" {
+			resp.Diagnostics.AddError(
+				"Invalid project type",
+				fmt.Sprintf("project_type must be one of: elasticsearch, observability, security. Got: %s", projectType),
+			)
+			return
+		}
+	} else {
+		if !resourceReady(r, &resp.Diagnostics) {
+			return
+		}
+
+		var diags diag.Diagnostics
+		projectType, diags = serverlessutil.DetectProjectType(ctx, r.client, projectID)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("%s-%s", projectID, trafficFilterID))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), AssociationID(projectID, trafficFilterID))...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_type"), projectType)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("traffic_filter_id"), trafficFilterID)...)
@@ -229,140 +404,152 @@ func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequ
 
 // getProjectTrafficFilters retrieves the current traffic filters for a project
 func (r *Resource) getProjectTrafficFilters(ctx context.Context, projectID, projectType string) ([]serverless.TrafficFilter, diag.Diagnostics) {
-	var diags diag.Diagnostics
+	project, diags := r.getProject(ctx, projectID, projectType)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return project.TrafficFilters, diags
+}
 
-	switch projectType {
-	case "elasticsearch":
-		resp, err := r.client.GetElasticsearchProjectWithResponse(ctx, projectID)
-		if err != nil {
-			diags.AddError("Failed to read project", err.Error())
-			return nil, diags
-		}
-		if resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
-			diags.AddError("Project not found", fmt.Sprintf("Elasticsearch project %s not found", projectID))
-			return nil, diags
-		}
-		if resp.JSON200 == nil {
-			diags.AddError(
-				"Failed to read project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s", resp.StatusCode(), resp.Status(), string(resp.Body)),
-			)
-			return nil, diags
-		}
-		if resp.JSON200.TrafficFilters == nil {
-			return []serverless.TrafficFilter{}, nil
-		}
-		return *resp.JSON200.TrafficFilters, nil
+// getProject retrieves the region and the current traffic filters of a project,
+// reusing a recent read of the same project if there is one
+func (r *Resource) getProject(ctx context.Context, projectID, projectType string) (serverlessutil.Project, diag.Diagnostics) {
+	if project, ok := r.projects.get(projectType, projectID); ok {
+		return project, nil
+	}
 
-	case "observability":
-		resp, err := r.client.GetObservabilityProjectWithResponse(ctx, projectID)
-		if err != nil {
-			diags.AddError("Failed to read project", err.Error())
-			return nil, diags
-		}
-		if resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
-			diags.AddError("Project not found", fmt.Sprintf("Observability project %s not found", projectID))
-			return nil, diags
-		}
-		if resp.JSON200 == nil {
-			diags.AddError(
-				"Failed to read project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s", resp.StatusCode(), resp.Status(), string(resp.Body)),
-			)
-			return nil, diags
-		}
-		if resp.JSON200.TrafficFilters == nil {
-			return []serverless.TrafficFilter{}, nil
-		}
-		return *resp.JSON200.TrafficFilters, nil
+	project, diags := serverlessutil.FetchProject(ctx, r.client, projectID, projectType)
+	if !diags.HasError() {
+		r.projects.put(projectType, projectID, project)
+	}
+	return project, diags
+}
 
-	case "security":
-		resp, err := r.client.GetSecurityProjectWithResponse(ctx, projectID)
-		if err != nil {
-			diags.AddError("Failed to read project", err.Error())
-			return nil, diags
-		}
-		if resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
-			diags.AddError("Project not found", fmt.Sprintf("Security project %s not found", projectID))
-			return nil, diags
-		}
-		if resp.JSON200 == nil {
-			diags.AddError(
-				"Failed to read project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s", resp.StatusCode(), resp.Status(), string(resp.Body)),
-			)
-			return nil, diags
-		}
-		if resp.JSON200.TrafficFilters == nil {
-			return []serverless.TrafficFilter{}, nil
-		}
-		return *resp.JSON200.TrafficFilters, nil
+// trafficFilterExists reports whether the traffic filter with the given ID exists
+func (r *Resource) trafficFilterExists(ctx context.Context, trafficFilterID string) (bool, diag.Diagnostics) {
+	filter, diags := r.getTrafficFilter(ctx, trafficFilterID)
+	return filter != nil, diags
+}
 
-	default:
-		diags.AddError("Invalid project type", fmt.Sprintf("Unknown project type: %s", projectType))
-		return nil, diags
+// keepDefaultFilterOnDestroy returns keep_default_filter_on_destroy, false if it isn't set yet,
+// e.g. for imported associations or ones created by earlier versions of the provider.
+func keepDefaultFilterOnDestroy(model modelV0) types.Bool {
+	if model.KeepDefaultFilterOnDestroy.IsNull() || model.KeepDefaultFilterOnDestroy.IsUnknown() {
+		return types.BoolValue(false)
 	}
+	return model.KeepDefaultFilterOnDestroy
 }
 
-// patchProjectTrafficFilters updates the traffic filters for a project
-func (r *Resource) patchProjectTrafficFilters(ctx context.Context, projectID, projectType string, filters []serverless.TrafficFilter) diag.Diagnostics {
+// getTrafficFilter reads a traffic filter, nil if it doesn't exist
+func (r *Resource) getTrafficFilter(ctx context.Context, trafficFilterID string) (*serverless.TrafficFilterInfo, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
+	resp, err := r.client.GetTrafficFilterWithResponse(ctx, trafficFilterID)
+	if err != nil {
+		diags.AddError(util.APIReadFailed, util.OperationDetail("read traffic filter", util.RequestErrorDetail(err)))
+		return nil, diags
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, diags
+	}
+	if resp.JSON200 == nil {
+		diags.AddError(
+			util.APIReadFailed,
+			util.OperationDetail("read traffic filter", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+		)
+		return nil, diags
+	}
+	return resp.JSON200, diags
+}
+
+// patchProjectTrafficFilters updates the traffic filters for a project. If an ETag is given, the
+// update is only applied if the project hasn't been modified since, otherwise patchConflict is returned.
+// patchNotReady is returned if the project can't be updated yet, as it's still being provisioned.
+func (r *Resource) patchProjectTrafficFilters(ctx context.Context, projectID, projectType string, filters []serverless.TrafficFilter, etag string) (outcome patchOutcome, diags diag.Diagnostics) {
+	// Whatever the outcome, the cached project may be outdated now.
+	defer r.projects.invalidate(projectType, projectID)
+
+	var ifMatch *string
+	if etag != "" {
+		ifMatch = &etag
+	}
+
 	switch projectType {
 	case "elasticsearch":
 		patchReq := serverless.PatchElasticsearchProjectRequest{
 			TrafficFilters: &filters,
 		}
-		resp, err := r.client.PatchElasticsearchProjectWithResponse(ctx, projectID, nil, patchReq)
+		var params *serverless.PatchElasticsearchProjectParams
+		if ifMatch != nil {
+			params = &serverless.PatchElasticsearchProjectParams{IfMatch: ifMatch}
+		}
+		resp, err := r.client.PatchElasticsearchProjectWithResponse(ctx, projectID, params, patchReq)
 		if err != nil {
-			diags.AddError("Failed to update project", err.Error())
-			return diags
+			diags.AddError(util.APIUpdateFailed, util.OperationDetail("update project", util.RequestErrorDetail(err)))
+			return patched, diags
+		}
+		if outcome := conflictOutcome(resp.StatusCode(), resp.Body); outcome != patched {
+			return outcome, diags
 		}
 		if resp.JSON200 == nil {
 			diags.AddError(
-				"Failed to update project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s", resp.StatusCode(), resp.Status(), string(resp.Body)),
+				util.APIUpdateFailed,
+				util.OperationDetail("update project", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
 			)
-			return diags
+			return patched, diags
 		}
 
 	case "observability":
 		patchReq := serverless.PatchObservabilityProjectRequest{
 			TrafficFilters: &filters,
 		}
-		resp, err := r.client.PatchObservabilityProjectWithResponse(ctx, projectID, nil, patchReq)
+		var params *serverless.PatchObservabilityProjectParams
+		if ifMatch != nil {
+			params = &serverless.PatchObservabilityProjectParams{IfMatch: ifMatch}
+		}
+		resp, err := r.client.PatchObservabilityProjectWithResponse(ctx, projectID, params, patchReq)
 		if err != nil {
-			diags.AddError("Failed to update project", err.Error())
-			return diags
+			diags.AddError(util.APIUpdateFailed, util.OperationDetail("update project", util.RequestErrorDetail(err)))
+			return patched, diags
+		}
+		if outcome := conflictOutcome(resp.StatusCode(), resp.Body); outcome != patched {
+			return outcome, diags
 		}
 		if resp.JSON200 == nil {
 			diags.AddError(
-				"Failed to update project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s", resp.StatusCode(), resp.Status(), string(resp.Body)),
+				util.APIUpdateFailed,
+				util.OperationDetail("update project", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
 			)
-			return diags
+			return patched, diags
 		}
 
 	case "security":
 		patchReq := serverless.PatchSecurityProjectRequest{
 			TrafficFilters: &filters,
 		}
-		resp, err := r.client.PatchSecurityProjectWithResponse(ctx, projectID, nil, patchReq)
+		var params *serverless.PatchSecurityProjectParams
+		if ifMatch != nil {
+			params = &serverless.PatchSecurityProjectParams{IfMatch: ifMatch}
+		}
+		resp, err := r.client.PatchSecurityProjectWithResponse(ctx, projectID, params, patchReq)
 		if err != nil {
-			diags.AddError("Failed to update project", err.Error())
-			return diags
+			diags.AddError(util.APIUpdateFailed, util.OperationDetail("update project", util.RequestErrorDetail(err)))
+			return patched, diags
+		}
+		if outcome := conflictOutcome(resp.StatusCode(), resp.Body); outcome != patched {
+			return outcome, diags
 		}
 		if resp.JSON200 == nil {
 			diags.AddError(
-				"Failed to update project",
-				fmt.Sprintf("The API request failed with: %d %s\n%s", resp.StatusCode(), resp.Status(), string(resp.Body)),
+				util.APIUpdateFailed,
+				util.OperationDetail("update project", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
 			)
-			return diags
+			return patched, diags
 		}
 
 	default:
 		diags.AddError("Invalid project type", fmt.Sprintf("Unknown project type: %s", projectType))
 	}
 
-	return diags
+	return patched, diags
 }
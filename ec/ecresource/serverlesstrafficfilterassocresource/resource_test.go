@@ -20,11 +20,21 @@ package serverlesstrafficfilterassocresource
 import (
 	"context"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -157,6 +167,33 @@ func TestGetProjectTrafficFilters_EmptyFilters(t *testing.T) {
 	require.Len(t, filters, 0)
 }
 
+func TestGetProjectTrafficFilters_ExplicitlyEmptyFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	projectID := "test-project-id"
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+
+	emptyFilters := serverless.TrafficFilters{}
+	getResp := &serverless.GetElasticsearchProjectResponse{
+		JSON200: &serverless.ElasticsearchProject{
+			Id:             projectID,
+			Name:           "test-project",
+			TrafficFilters: &emptyFilters,
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(ctx, projectID).Return(getResp, nil)
+
+	r := &Resource{client: mockClient}
+	filters, diags := r.getProjectTrafficFilters(ctx, projectID, "elasticsearch")
+
+	require.False(t, diags.HasError())
+	require.NotNil(t, filters)
+	require.Len(t, filters, 0)
+}
+
 func TestGetProjectTrafficFilters_InvalidProjectType(t *testing.T) {
 	ctx := context.Background()
 
@@ -191,7 +228,7 @@ func TestPatchProjectTrafficFilters_Elasticsearch(t *testing.T) {
 
 	r := &Resource{client: mockClient}
 	filters := []serverless.TrafficFilter{{Id: filterID}}
-	diags := r.patchProjectTrafficFilters(ctx, projectID, "elasticsearch", filters)
+	_, diags := r.patchProjectTrafficFilters(ctx, projectID, "elasticsearch", filters, "")
 
 	require.False(t, diags.HasError())
 }
@@ -221,7 +258,7 @@ func TestPatchProjectTrafficFilters_Observability(t *testing.T) {
 
 	r := &Resource{client: mockClient}
 	filters := []serverless.TrafficFilter{{Id: filterID}}
-	diags := r.patchProjectTrafficFilters(ctx, projectID, "observability", filters)
+	_, diags := r.patchProjectTrafficFilters(ctx, projectID, "observability", filters, "")
 
 	require.False(t, diags.HasError())
 }
@@ -251,7 +288,7 @@ func TestPatchProjectTrafficFilters_Security(t *testing.T) {
 
 	r := &Resource{client: mockClient}
 	filters := []serverless.TrafficFilter{{Id: filterID}}
-	diags := r.patchProjectTrafficFilters(ctx, projectID, "security", filters)
+	_, diags := r.patchProjectTrafficFilters(ctx, projectID, "security", filters, "")
 
 	require.False(t, diags.HasError())
 }
@@ -276,7 +313,7 @@ func TestPatchProjectTrafficFilters_Error(t *testing.T) {
 
 	r := &Resource{client: mockClient}
 	filters := []serverless.TrafficFilter{{Id: "filter-id"}}
-	diags := r.patchProjectTrafficFilters(ctx, projectID, "elasticsearch", filters)
+	_, diags := r.patchProjectTrafficFilters(ctx, projectID, "elasticsearch", filters, "")
 
 	require.True(t, diags.HasError())
 }
@@ -303,3 +340,1032 @@ func TestResourceReady(t *testing.T) {
 		require.False(t, diags.HasError())
 	})
 }
+
+func TestCreate_ByTrafficFilterName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewResource().(*Resource)
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	plan := modelV0{
+		ID:                types.StringUnknown(),
+		ProjectID:         types.StringValue("project-id"),
+		ProjectName:       types.StringUnknown(),
+		ProjectType:       types.StringValue("elasticsearch"),
+		TrafficFilterID:   types.StringUnknown(),
+		TrafficFilterName: types.StringValue("my-filter"),
+		Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	}
+
+	region := "us-east-1"
+	existingFilters := serverless.TrafficFilters{{Id: "existing-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200: &serverless.ElasticsearchProject{
+			Id:             "project-id",
+			Name:           "my-project",
+			RegionId:       region,
+			TrafficFilters: &existingFilters,
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().ListTrafficFiltersWithResponse(gomock.Any(), &serverless.ListTrafficFiltersParams{Region: &region}).Return(&serverless.ListTrafficFiltersResponse{
+		JSON200: &serverless.TrafficFilterList{Items: []serverless.TrafficFilterInfo{
+			{Id: "resolved-id", Name: "my-filter", Region: region},
+		}},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().PatchElasticsearchProjectWithResponse(gomock.Any(),
+		"project-id",
+		(*serverless.PatchElasticsearchProjectParams)(nil),
+		serverless.PatchElasticsearchProjectRequest{
+			TrafficFilters: &[]serverless.TrafficFilter{{Id: "existing-id"}, {Id: "resolved-id"}},
+		},
+	).Return(&serverless.PatchElasticsearchProjectResponse{
+		JSON200:      &serverless.ElasticsearchProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	r.client = mockClient
+
+	req := resource.CreateRequest{
+		Plan: tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, plan, schemaResp.Schema.Type()),
+		},
+	}
+	resp := resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	r.Create(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state modelV0
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, "resolved-id", state.TrafficFilterID.ValueString())
+	require.Equal(t, "my-filter", state.TrafficFilterName.ValueString())
+	require.Equal(t, "project-id,resolved-id", state.ID.ValueString())
+	require.Equal(t, AssociationID("project-id", "resolved-id"), state.ID.ValueString())
+	require.Equal(t, "my-project", state.ProjectName.ValueString())
+}
+
+func TestCreate_WarnsIfAlreadyAssociated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewResource().(*Resource)
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	plan := modelV0{
+		ID:                types.StringUnknown(),
+		ProjectID:         types.StringValue("project-id"),
+		ProjectName:       types.StringUnknown(),
+		ProjectType:       types.StringValue("security"),
+		TrafficFilterID:   types.StringValue("filter-id"),
+		TrafficFilterName: types.StringNull(),
+		Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	}
+
+	existingFilters := serverless.TrafficFilters{{Id: "filter-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+		JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-project", TrafficFilters: &existingFilters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	expectTrafficFilter(mockClient, "filter-id", http.StatusOK)
+	r.client = mockClient
+
+	req := resource.CreateRequest{
+		Plan: tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, plan, schemaResp.Schema.Type()),
+		},
+	}
+	resp := resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	r.Create(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	require.Equal(t, "Traffic filter already associated", resp.Diagnostics.Warnings()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "only one ec_serverless_traffic_filter_association resource")
+
+	var state modelV0
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, AssociationID("project-id", "filter-id"), state.ID.ValueString())
+}
+
+func TestRead_SetsProjectName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewResource().(*Resource)
+
+	// Imported associations don't know the project name yet.
+	prior := modelV0{
+		ID:                types.StringValue("project-id-filter-id"),
+		ProjectID:         types.StringValue("project-id"),
+		ProjectName:       types.StringNull(),
+		ProjectType:       types.StringValue("security"),
+		TrafficFilterID:   types.StringValue("filter-id"),
+		TrafficFilterName: types.StringNull(),
+		Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	}
+
+	existingFilters := serverless.TrafficFilters{{Id: "filter-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectTrafficFilter(mockClient, "filter-id", http.StatusOK)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+		JSON200: &serverless.SecurityProject{
+			Id:             "project-id",
+			Name:           "my-security-project",
+			TrafficFilters: &existingFilters,
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	r.client = mockClient
+
+	resp := readResource(t, r, prior)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(ctx, &model).HasError())
+	require.Equal(t, "my-security-project", model.ProjectName.ValueString())
+	// The legacy ID of the prior state is migrated.
+	require.Equal(t, "project-id,filter-id", model.ID.ValueString())
+}
+
+// readTimeout returns a timeouts attribute setting the read timeout.
+func readTimeout(timeout string) types.Object {
+	return types.ObjectValueMust(timeouts.AttrTypes(timeoutOpts), map[string]attr.Value{
+		"create": types.StringNull(),
+		"read":   types.StringValue(timeout),
+		"delete": types.StringNull(),
+	})
+}
+
+func TestRead_RemovesAssociationWithoutProjectFilters(t *testing.T) {
+	emptyFilters := serverless.TrafficFilters{}
+	tests := []struct {
+		name    string
+		filters *serverless.TrafficFilters
+	}{
+		{name: "absent traffic filters", filters: nil},
+		{name: "explicitly empty traffic filters", filters: &emptyFilters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			r := NewResource().(*Resource)
+			prior := modelV0{
+				ID:                types.StringValue("project-id-filter-id"),
+				ProjectID:         types.StringValue("project-id"),
+				ProjectName:       types.StringValue("my-security-project"),
+				ProjectType:       types.StringValue("security"),
+				TrafficFilterID:   types.StringValue("filter-id"),
+				TrafficFilterName: types.StringNull(),
+				Timeouts:          readTimeout("5s"),
+			}
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			expectTrafficFilter(mockClient, "filter-id", http.StatusOK)
+			mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+				JSON200: &serverless.SecurityProject{
+					Id:             "project-id",
+					Name:           "my-security-project",
+					TrafficFilters: tt.filters,
+				},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil).Times(3)
+			r.client = mockClient
+			var slept []time.Duration
+			r.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+			resp := readResource(t, r, prior)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			require.True(t, resp.State.Raw.IsNull())
+			// The backoff grows until the read timeout is reached.
+			require.Equal(t, []time.Duration{2 * time.Second, 3 * time.Second}, slept)
+		})
+	}
+}
+
+func TestRead_RetriesMissingAssociation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewResource().(*Resource)
+	prior := modelV0{
+		ID:                         types.StringValue("project-id,filter-id"),
+		ProjectID:                  types.StringValue("project-id"),
+		ProjectName:                types.StringValue("my-security-project"),
+		ProjectType:                types.StringValue("security"),
+		TrafficFilterID:            types.StringValue("filter-id"),
+		TrafficFilterName:          types.StringNull(),
+		KeepDefaultFilterOnDestroy: types.BoolValue(false),
+		Timeouts:                   readTimeout("1m"),
+	}
+
+	withoutFilter := serverless.TrafficFilters{{Id: "other-id"}}
+	withFilter := serverless.TrafficFilters{{Id: "other-id"}, {Id: "filter-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectTrafficFilter(mockClient, "filter-id", http.StatusOK)
+	gomock.InOrder(
+		mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+			JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-security-project", TrafficFilters: &withoutFilter},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+			JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-security-project", TrafficFilters: &withFilter},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+	)
+	r.client = mockClient
+	var slept []time.Duration
+	r.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	resp := readResource(t, r, prior)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.False(t, resp.State.Raw.IsNull())
+	require.Equal(t, []time.Duration{2 * time.Second}, slept)
+
+	var model modelV0
+	require.False(t, resp.State.Get(ctx, &model).HasError())
+	require.Equal(t, prior, model)
+}
+
+func importState(t *testing.T, r *Resource, id string) resource.ImportStateResponse {
+	ctx := context.Background()
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+	return resp
+}
+
+func TestImportState_DetectsProjectType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(ctx, "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+		JSON200:      &serverless.ObservabilityProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	resp := importState(t, &Resource{client: mockClient}, "project-id,filter-id")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(ctx, &model).HasError())
+	require.Equal(t, "project-id,filter-id", model.ID.ValueString())
+	require.Equal(t, "project-id", model.ProjectID.ValueString())
+	require.Equal(t, "observability", model.ProjectType.ValueString())
+	require.Equal(t, "filter-id", model.TrafficFilterID.ValueString())
+}
+
+func TestImportState_ProjectNotFoundUnderAnyType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(ctx, "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(ctx, "project-id").Return(&serverless.GetSecurityProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+
+	resp := importState(t, &Resource{client: mockClient}, "project-id,filter-id")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Project not found", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func TestImportState_ExplicitProjectType(t *testing.T) {
+	resp := importState(t, &Resource{}, "project-id,security,filter-id")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(context.Background(), &model).HasError())
+	require.Equal(t, "security", model.ProjectType.ValueString())
+}
+
+func expectTrafficFilter(mockClient *mocks.MockClientWithResponsesInterface, filterID string, statusCode int) *gomock.Call {
+	resp := &serverless.GetTrafficFilterResponse{
+		HTTPResponse: &http.Response{StatusCode: statusCode},
+	}
+	if statusCode == http.StatusOK {
+		resp.JSON200 = &serverless.TrafficFilterInfo{Id: filterID}
+	}
+	return mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), filterID).Return(resp, nil)
+}
+
+func readResource(t *testing.T, r *Resource, prior modelV0) resource.ReadResponse {
+	ctx := context.Background()
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    util.TfTypesValueFromGoTypeValue(t, prior, schemaResp.Schema.Type()),
+	}
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	return resp
+}
+
+func TestCreate_MissingTrafficFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewResource().(*Resource)
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	plan := modelV0{
+		ID:                types.StringUnknown(),
+		ProjectID:         types.StringValue("project-id"),
+		ProjectName:       types.StringUnknown(),
+		ProjectType:       types.StringValue("elasticsearch"),
+		TrafficFilterID:   types.StringValue("deleted-id"),
+		TrafficFilterName: types.StringNull(),
+		Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	}
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200:      &serverless.ElasticsearchProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	expectTrafficFilter(mockClient, "deleted-id", http.StatusNotFound)
+	r.client = mockClient
+
+	req := resource.CreateRequest{
+		Plan: tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw:    util.TfTypesValueFromGoTypeValue(t, plan, schemaResp.Schema.Type()),
+		},
+	}
+	resp := resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	r.Create(ctx, req, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter not found", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func TestRead_MissingTrafficFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectTrafficFilter(mockClient, "deleted-id", http.StatusNotFound)
+
+	resp := readResource(t, &Resource{client: mockClient}, modelV0{
+		ID:                types.StringValue("project-id-deleted-id"),
+		ProjectID:         types.StringValue("project-id"),
+		ProjectName:       types.StringValue("my-project"),
+		ProjectType:       types.StringValue("elasticsearch"),
+		TrafficFilterID:   types.StringValue("deleted-id"),
+		TrafficFilterName: types.StringNull(),
+		Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	})
+
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	require.Equal(t, "Traffic filter not found", resp.Diagnostics.Warnings()[0].Summary())
+	require.True(t, resp.State.Raw.IsNull())
+}
+
+func deleteResource(t *testing.T, r *Resource, prior modelV0) resource.DeleteResponse {
+	ctx := context.Background()
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    util.TfTypesValueFromGoTypeValue(t, prior, schemaResp.Schema.Type()),
+	}
+	resp := resource.DeleteResponse{State: state}
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+	return resp
+}
+
+func TestDelete_KeepDefaultFilterOnDestroy(t *testing.T) {
+	tests := []struct {
+		name             string
+		includeByDefault bool
+		expectPatch      bool
+	}{
+		{name: "keeps traffic filters included by default", includeByDefault: true},
+		{name: "removes other traffic filters", includeByDefault: false, expectPatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			r := NewResource().(*Resource)
+			prior := modelV0{
+				ID:                         types.StringValue("project-id,filter-id"),
+				ProjectID:                  types.StringValue("project-id"),
+				ProjectName:                types.StringValue("my-project"),
+				ProjectType:                types.StringValue("security"),
+				TrafficFilterID:            types.StringValue("filter-id"),
+				TrafficFilterName:          types.StringNull(),
+				KeepDefaultFilterOnDestroy: types.BoolValue(true),
+				Timeouts:                   types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+			}
+
+			filters := serverless.TrafficFilters{{Id: "other-id"}, {Id: "filter-id"}}
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+				JSON200:      &serverless.TrafficFilterInfo{Id: "filter-id", IncludeByDefault: tt.includeByDefault},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+			if tt.expectPatch {
+				mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+					JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-project", TrafficFilters: &filters},
+					HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+				}, nil)
+				mockClient.EXPECT().PatchSecurityProjectWithResponse(gomock.Any(), "project-id", gomock.Any(),
+					serverless.PatchSecurityProjectRequest{TrafficFilters: &[]serverless.TrafficFilter{{Id: "other-id"}}},
+				).Return(&serverless.PatchSecurityProjectResponse{
+					JSON200:      &serverless.SecurityProject{Id: "project-id"},
+					HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+				}, nil)
+			}
+			r.client = mockClient
+
+			resp := deleteResource(t, r, prior)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			if tt.expectPatch {
+				require.Empty(t, resp.Diagnostics.Warnings())
+			} else {
+				require.Len(t, resp.Diagnostics.Warnings(), 1)
+				require.Equal(t, "Traffic filter association kept", resp.Diagnostics.Warnings()[0].Summary())
+			}
+		})
+	}
+}
+
+func TestUpdate_KeepDefaultFilterOnDestroy(t *testing.T) {
+	ctx := context.Background()
+
+	r := NewResource().(*Resource)
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	state := modelV0{
+		ID:                         types.StringValue("project-id,filter-id"),
+		ProjectID:                  types.StringValue("project-id"),
+		ProjectName:                types.StringValue("my-project"),
+		ProjectType:                types.StringValue("security"),
+		TrafficFilterID:            types.StringValue("filter-id"),
+		TrafficFilterName:          types.StringNull(),
+		KeepDefaultFilterOnDestroy: types.BoolValue(false),
+		Timeouts:                   types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	}
+	plan := state
+	plan.KeepDefaultFilterOnDestroy = types.BoolValue(true)
+
+	// The client isn't configured, updates don't call the API.
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Update(ctx, resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, plan, schemaResp.Schema.Type())},
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, state, schemaResp.Schema.Type())},
+	}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var updated modelV0
+	require.False(t, resp.State.Get(ctx, &updated).HasError())
+	require.Equal(t, plan, updated)
+}
+
+func TestImportState_AcceptsLegacyID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200:      &serverless.ElasticsearchProject{Id: "project"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	resp := importState(t, &Resource{client: mockClient}, "project-filter")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(context.Background(), &model).HasError())
+	require.Equal(t, "project,filter", model.ID.ValueString())
+	require.Equal(t, "project", model.ProjectID.ValueString())
+	require.Equal(t, "filter", model.TrafficFilterID.ValueString())
+}
+
+func TestImportState_TrimsWhitespace(t *testing.T) {
+	resp := importState(t, &Resource{}, "  project-id , security ,\tfilter-id \n")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(context.Background(), &model).HasError())
+	require.Equal(t, "project-id,filter-id", model.ID.ValueString())
+	require.Equal(t, "project-id", model.ProjectID.ValueString())
+	require.Equal(t, "security", model.ProjectType.ValueString())
+	require.Equal(t, "filter-id", model.TrafficFilterID.ValueString())
+}
+
+func addFilter(id string) func([]serverless.TrafficFilter) ([]serverless.TrafficFilter, bool) {
+	return func(current []serverless.TrafficFilter) ([]serverless.TrafficFilter, bool) {
+		return append(append([]serverless.TrafficFilter{}, current...), serverless.TrafficFilter{Id: id}), true
+	}
+}
+
+func TestUpdateProjectTrafficFilters_RetriesOnConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	v1, v2 := `"v1"`, `"v2"`
+	concurrentFilters := serverless.TrafficFilters{{Id: "existing-id"}, {Id: "concurrent-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	gomock.InOrder(
+		mockClient.EXPECT().PatchSecurityProjectWithResponse(
+			ctx,
+			"project-id",
+			&serverless.PatchSecurityProjectParams{IfMatch: &v1},
+			serverless.PatchSecurityProjectRequest{TrafficFilters: &[]serverless.TrafficFilter{{Id: "existing-id"}, {Id: "new-id"}}},
+		).Return(&serverless.PatchSecurityProjectResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusPreconditionFailed},
+		}, nil),
+		mockClient.EXPECT().GetSecurityProjectWithResponse(ctx, "project-id").Return(&serverless.GetSecurityProjectResponse{
+			JSON200:      &serverless.SecurityProject{Id: "project-id", TrafficFilters: &concurrentFilters},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Etag": []string{v2}}},
+		}, nil),
+		mockClient.EXPECT().PatchSecurityProjectWithResponse(
+			ctx,
+			"project-id",
+			&serverless.PatchSecurityProjectParams{IfMatch: &v2},
+			serverless.PatchSecurityProjectRequest{TrafficFilters: &[]serverless.TrafficFilter{{Id: "existing-id"}, {Id: "concurrent-id"}, {Id: "new-id"}}},
+		).Return(&serverless.PatchSecurityProjectResponse{
+			JSON200:      &serverless.SecurityProject{Id: "project-id"},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+	)
+
+	r := &Resource{client: mockClient}
+	project := serverlessutil.Project{ETag: v1, TrafficFilters: []serverless.TrafficFilter{{Id: "existing-id"}}}
+	diags := r.updateProjectTrafficFilters(ctx, "project-id", "security", project, defaultNotReadyTimeout, addFilter("new-id"))
+
+	require.False(t, diags.HasError(), diags)
+}
+
+func TestUpdateProjectTrafficFilters_GivesUpAfterRepeatedConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().PatchElasticsearchProjectWithResponse(ctx, "project-id", gomock.Any(), gomock.Any()).Return(&serverless.PatchElasticsearchProjectResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusPreconditionFailed},
+	}, nil).Times(maxConflictRetries + 1)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(ctx, "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200:      &serverless.ElasticsearchProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Etag": []string{`"changed"`}}},
+	}, nil).Times(maxConflictRetries)
+
+	r := &Resource{client: mockClient}
+	project := serverlessutil.Project{ETag: `"v1"`, TrafficFilters: []serverless.TrafficFilter{}}
+	diags := r.updateProjectTrafficFilters(ctx, "project-id", "elasticsearch", project, defaultNotReadyTimeout, addFilter("new-id"))
+
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "modified concurrently 4 times in a row")
+}
+
+func TestUpdateProjectTrafficFilters_SkipsPatchWithoutChanges(t *testing.T) {
+	r := &Resource{client: mocks.NewMockClientWithResponsesInterface(gomock.NewController(t))}
+	diags := r.updateProjectTrafficFilters(context.Background(), "project-id", "elasticsearch", serverlessutil.Project{}, defaultNotReadyTimeout, func(current []serverless.TrafficFilter) ([]serverless.TrafficFilter, bool) {
+		return current, false
+	})
+
+	require.False(t, diags.HasError())
+}
+
+type projectTypeExpectations struct {
+	projectType string
+	expectGet   func(mockClient *mocks.MockClientWithResponsesInterface, filters serverless.TrafficFilters) *gomock.Call
+	expectPatch func(mockClient *mocks.MockClientWithResponsesInterface, filters []serverless.TrafficFilter) *gomock.Call
+}
+
+var lifecycleProjectTypes = []projectTypeExpectations{
+	{
+		projectType: "elasticsearch",
+		expectGet: func(mockClient *mocks.MockClientWithResponsesInterface, filters serverless.TrafficFilters) *gomock.Call {
+			return mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+				JSON200:      &serverless.ElasticsearchProject{Id: "project-id", Name: "my-project", TrafficFilters: &filters},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+		},
+		expectPatch: func(mockClient *mocks.MockClientWithResponsesInterface, filters []serverless.TrafficFilter) *gomock.Call {
+			return mockClient.EXPECT().PatchElasticsearchProjectWithResponse(gomock.Any(), "project-id", gomock.Any(),
+				serverless.PatchElasticsearchProjectRequest{TrafficFilters: &filters},
+			).Return(&serverless.PatchElasticsearchProjectResponse{
+				JSON200:      &serverless.ElasticsearchProject{Id: "project-id"},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+		},
+	},
+	{
+		projectType: "observability",
+		expectGet: func(mockClient *mocks.MockClientWithResponsesInterface, filters serverless.TrafficFilters) *gomock.Call {
+			return mockClient.EXPECT().GetObservabilityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetObservabilityProjectResponse{
+				JSON200:      &serverless.ObservabilityProject{Id: "project-id", Name: "my-project", TrafficFilters: &filters},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+		},
+		expectPatch: func(mockClient *mocks.MockClientWithResponsesInterface, filters []serverless.TrafficFilter) *gomock.Call {
+			return mockClient.EXPECT().PatchObservabilityProjectWithResponse(gomock.Any(), "project-id", gomock.Any(),
+				serverless.PatchObservabilityProjectRequest{TrafficFilters: &filters},
+			).Return(&serverless.PatchObservabilityProjectResponse{
+				JSON200:      &serverless.ObservabilityProject{Id: "project-id"},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+		},
+	},
+	{
+		projectType: "security",
+		expectGet: func(mockClient *mocks.MockClientWithResponsesInterface, filters serverless.TrafficFilters) *gomock.Call {
+			return mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetSecurityProjectResponse{
+				JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-project", TrafficFilters: &filters},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+		},
+		expectPatch: func(mockClient *mocks.MockClientWithResponsesInterface, filters []serverless.TrafficFilter) *gomock.Call {
+			return mockClient.EXPECT().PatchSecurityProjectWithResponse(gomock.Any(), "project-id", gomock.Any(),
+				serverless.PatchSecurityProjectRequest{TrafficFilters: &filters},
+			).Return(&serverless.PatchSecurityProjectResponse{
+				JSON200:      &serverless.SecurityProject{Id: "project-id"},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+		},
+	},
+}
+
+// TestLifecycle runs create, read and delete for every project type, making
+// sure each step only talks to the endpoints of that project type.
+func TestLifecycle(t *testing.T) {
+	for _, tt := range lifecycleProjectTypes {
+		t.Run(tt.projectType, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			r := NewResource().(*Resource)
+			schemaResp := resource.SchemaResponse{}
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+			require.False(t, schemaResp.Diagnostics.HasError())
+
+			before := []serverless.TrafficFilter{{Id: "existing-id"}}
+			after := []serverless.TrafficFilter{{Id: "existing-id"}, {Id: "filter-id"}}
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			gomock.InOrder(
+				// Create
+				tt.expectGet(mockClient, before),
+				expectTrafficFilter(mockClient, "filter-id", http.StatusOK),
+				tt.expectPatch(mockClient, after),
+				// Read
+				expectTrafficFilter(mockClient, "filter-id", http.StatusOK),
+				tt.expectGet(mockClient, after),
+				// Delete
+				tt.expectGet(mockClient, after),
+				tt.expectPatch(mockClient, before),
+			)
+			r.client = mockClient
+
+			plan := modelV0{
+				ID:                types.StringUnknown(),
+				ProjectID:         types.StringValue("project-id"),
+				ProjectName:       types.StringUnknown(),
+				ProjectType:       types.StringValue(tt.projectType),
+				TrafficFilterID:   types.StringValue("filter-id"),
+				TrafficFilterName: types.StringNull(),
+				Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+			}
+			createResp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+			r.Create(ctx, resource.CreateRequest{
+				Plan: tfsdk.Plan{
+					Schema: schemaResp.Schema,
+					Raw:    util.TfTypesValueFromGoTypeValue(t, plan, schemaResp.Schema.Type()),
+				},
+			}, &createResp)
+			require.False(t, createResp.Diagnostics.HasError(), createResp.Diagnostics)
+
+			var created modelV0
+			require.False(t, createResp.State.Get(ctx, &created).HasError())
+			require.Equal(t, "project-id,filter-id", created.ID.ValueString())
+			require.Equal(t, AssociationID("project-id", "filter-id"), created.ID.ValueString())
+			require.Equal(t, "my-project", created.ProjectName.ValueString())
+
+			readResp := readResource(t, r, created)
+			require.False(t, readResp.Diagnostics.HasError(), readResp.Diagnostics)
+
+			var read modelV0
+			require.False(t, readResp.State.Get(ctx, &read).HasError())
+			require.Equal(t, created, read)
+
+			deleteResp := resource.DeleteResponse{State: readResp.State}
+			r.Delete(ctx, resource.DeleteRequest{State: readResp.State}, &deleteResp)
+			require.False(t, deleteResp.Diagnostics.HasError(), deleteResp.Diagnostics)
+		})
+	}
+}
+
+func TestRead_SetsCloudProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	existingFilters := serverless.TrafficFilters{{Id: "filter-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectTrafficFilter(mockClient, "filter-id", http.StatusOK)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(gomock.Any(), "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200: &serverless.ElasticsearchProject{
+			Id:             "project-id",
+			Name:           "my-project",
+			RegionId:       "aws-us-east-1",
+			TrafficFilters: &existingFilters,
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	resp := readResource(t, &Resource{client: mockClient}, modelV0{
+		ID:                types.StringValue("project-id-filter-id"),
+		CloudProvider:     types.StringNull(),
+		ProjectID:         types.StringValue("project-id"),
+		ProjectName:       types.StringValue("my-project"),
+		ProjectType:       types.StringValue("elasticsearch"),
+		TrafficFilterID:   types.StringValue("filter-id"),
+		TrafficFilterName: types.StringNull(),
+		Timeouts:          types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+	})
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(context.Background(), &model).HasError())
+	require.Equal(t, "aws", model.CloudProvider.ValueString())
+}
+
+func TestGetProject_ReusesRecentRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	filters := serverless.TrafficFilters{{Id: "filter-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetSecurityProjectWithResponse(ctx, "project-id").Return(&serverless.GetSecurityProjectResponse{
+		JSON200:      &serverless.SecurityProject{Id: "project-id", Name: "my-project", TrafficFilters: &filters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).Times(2)
+
+	now := time.Now()
+	cache := newProjectCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	r := &Resource{client: mockClient, projects: cache}
+
+	first, diags := r.getProject(ctx, "project-id", "security")
+	require.False(t, diags.HasError(), diags)
+	second, diags := r.getProject(ctx, "project-id", "security")
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, first, second)
+
+	// Another project type is a different project.
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(ctx, "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200:      &serverless.ElasticsearchProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	_, diags = r.getProject(ctx, "project-id", "elasticsearch")
+	require.False(t, diags.HasError(), diags)
+
+	// Reads older than the TTL are not reused.
+	now = now.Add(time.Minute)
+	_, diags = r.getProject(ctx, "project-id", "security")
+	require.False(t, diags.HasError(), diags)
+}
+
+func TestRead_ReadsEachProjectOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	projectIDs := []string{"project-1", "project-2"}
+	filterIDs := []string{"filter-1", "filter-2", "filter-3"}
+	filters := serverless.TrafficFilters{{Id: "filter-1"}, {Id: "filter-2"}, {Id: "filter-3"}}
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	for _, filterID := range filterIDs {
+		expectTrafficFilter(mockClient, filterID, http.StatusOK).AnyTimes()
+	}
+	for _, projectID := range projectIDs {
+		mockClient.EXPECT().GetSecurityProjectWithResponse(gomock.Any(), projectID).Return(&serverless.GetSecurityProjectResponse{
+			JSON200:      &serverless.SecurityProject{Id: projectID, Name: projectID, TrafficFilters: &filters},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil).Times(1)
+	}
+
+	// Every association is refreshed by its own resource instance, sharing the project cache.
+	cache := newProjectCache(time.Minute)
+	var wg sync.WaitGroup
+	for _, projectID := range projectIDs {
+		for _, filterID := range filterIDs {
+			wg.Add(1)
+			go func(projectID, filterID string) {
+				defer wg.Done()
+				r := &Resource{client: mockClient, projects: cache}
+				resp := readResource(t, r, modelV0{
+					ID:                         types.StringValue(AssociationID(projectID, filterID)),
+					ProjectID:                  types.StringValue(projectID),
+					ProjectName:                types.StringNull(),
+					ProjectType:                types.StringValue("security"),
+					TrafficFilterID:            types.StringValue(filterID),
+					TrafficFilterName:          types.StringNull(),
+					KeepDefaultFilterOnDestroy: types.BoolValue(false),
+					Timeouts:                   types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+				})
+				require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			}(projectID, filterID)
+		}
+	}
+	wg.Wait()
+}
+
+func TestGetProject_PatchInvalidatesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	before := serverless.TrafficFilters{}
+	after := serverless.TrafficFilters{{Id: "filter-id"}}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	gomock.InOrder(
+		mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id", TrafficFilters: &before},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		mockClient.EXPECT().PatchObservabilityProjectWithResponse(ctx, "project-id", gomock.Any(), gomock.Any()).Return(&serverless.PatchObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id"},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id", TrafficFilters: &after},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+	)
+
+	r := &Resource{client: mockClient, projects: newProjectCache(time.Minute)}
+
+	project, diags := r.getProject(ctx, "project-id", "observability")
+	require.False(t, diags.HasError(), diags)
+	require.Empty(t, project.TrafficFilters)
+
+	_, diags = r.patchProjectTrafficFilters(ctx, "project-id", "observability", after, "")
+	require.False(t, diags.HasError(), diags)
+
+	project, diags = r.getProject(ctx, "project-id", "observability")
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, []serverless.TrafficFilter(after), project.TrafficFilters)
+}
+
+func TestProjectCache_LockSerializesSameProject(t *testing.T) {
+	cache := newProjectCache(time.Minute)
+
+	unlock := cache.lock("security", "project-id")
+	// Other projects aren't blocked.
+	cache.lock("security", "other-project-id")()
+
+	acquired := make(chan struct{})
+	go func() {
+		defer cache.lock("security", "project-id")()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-acquired
+}
+
+func TestUpdateProjectTrafficFilters_WaitsForProjectToBeReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	notReady := &serverless.PatchObservabilityProjectResponse{
+		Body:         []byte(`{"errors":[{"message":"project not ready"}]}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusConflict},
+	}
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	gomock.InOrder(
+		mockClient.EXPECT().PatchObservabilityProjectWithResponse(ctx, "project-id", gomock.Any(), gomock.Any()).Return(notReady, nil),
+		mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id"},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		mockClient.EXPECT().PatchObservabilityProjectWithResponse(ctx, "project-id", gomock.Any(), gomock.Any()).Return(notReady, nil),
+		mockClient.EXPECT().GetObservabilityProjectWithResponse(ctx, "project-id").Return(&serverless.GetObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id"},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		mockClient.EXPECT().PatchObservabilityProjectWithResponse(
+			ctx,
+			"project-id",
+			nil,
+			serverless.PatchObservabilityProjectRequest{TrafficFilters: &[]serverless.TrafficFilter{{Id: "new-id"}}},
+		).Return(&serverless.PatchObservabilityProjectResponse{
+			JSON200:      &serverless.ObservabilityProject{Id: "project-id"},
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+	)
+
+	var slept []time.Duration
+	r := &Resource{client: mockClient, sleep: func(d time.Duration) { slept = append(slept, d) }}
+	diags := r.updateProjectTrafficFilters(ctx, "project-id", "observability", serverlessutil.Project{}, defaultNotReadyTimeout, addFilter("new-id"))
+
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, []time.Duration{notReadyPollInterval, notReadyPollInterval}, slept)
+}
+
+func TestUpdateProjectTrafficFilters_GivesUpWaitingForProjectToBeReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	polls := int(defaultNotReadyTimeout / notReadyPollInterval)
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().PatchElasticsearchProjectWithResponse(ctx, "project-id", gomock.Any(), gomock.Any()).Return(&serverless.PatchElasticsearchProjectResponse{
+		Body:         []byte(`{"errors":[{"message":"Project Not Ready"}]}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusConflict},
+	}, nil).Times(polls + 1)
+	mockClient.EXPECT().GetElasticsearchProjectWithResponse(ctx, "project-id").Return(&serverless.GetElasticsearchProjectResponse{
+		JSON200:      &serverless.ElasticsearchProject{Id: "project-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).Times(polls)
+
+	r := &Resource{client: mockClient, sleep: func(time.Duration) {}}
+	diags := r.updateProjectTrafficFilters(ctx, "project-id", "elasticsearch", serverlessutil.Project{}, defaultNotReadyTimeout, addFilter("new-id"))
+
+	require.True(t, diags.HasError())
+	require.Equal(t, "Project not ready", diags.Errors()[0].Summary())
+	require.Contains(t, diags.Errors()[0].Detail(), "transitional state")
+}
+
+func TestUpdateProjectTrafficFilters_StopsWaitingWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().PatchElasticsearchProjectWithResponse(ctx, "project-id", gomock.Any(), gomock.Any()).Return(&serverless.PatchElasticsearchProjectResponse{
+		Body:         []byte(`{"errors":[{"message":"project not ready"}]}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusConflict},
+	}, nil)
+
+	r := &Resource{client: mockClient, sleep: func(time.Duration) { cancel() }}
+	diags := r.updateProjectTrafficFilters(ctx, "project-id", "elasticsearch", serverlessutil.Project{}, defaultNotReadyTimeout, addFilter("new-id"))
+
+	require.True(t, diags.HasError())
+	require.Equal(t, "Project not ready", diags.Errors()[0].Summary())
+	require.Contains(t, diags.Errors()[0].Detail(), context.Canceled.Error())
+}
+
+func TestConflictOutcome(t *testing.T) {
+	require.Equal(t, patchNotReady, conflictOutcome(http.StatusConflict, []byte(`{"message":"project not ready"}`)))
+	require.Equal(t, patchConflict, conflictOutcome(http.StatusConflict, []byte(`{"message":"version conflict"}`)))
+	require.Equal(t, patchConflict, conflictOutcome(http.StatusPreconditionFailed, []byte(`project not ready`)))
+	require.Equal(t, patched, conflictOutcome(http.StatusOK, nil))
+}
+
+func TestConfigure_UnexpectedProviderData(t *testing.T) {
+	r := &Resource{}
+	resp := resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: "not provider clients"}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Unexpected Provider Data", resp.Diagnostics.Errors()[0].Summary())
+	require.Nil(t, r.client)
+	require.Nil(t, r.projects)
+}
@@ -21,14 +21,22 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
 )
 
+// timeoutOpts selects the configurable timeouts, associations being created and deleted
+// only once the project is ready, and read until the project lists them.
+var timeoutOpts = timeouts.Opts{Create: true, Read: true, Delete: true}
+
 func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `Provides an Elastic Cloud serverless traffic filter association resource, which allows traffic filter rules to be associated with a serverless project. Associations can be created and deleted.
@@ -59,20 +67,59 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 					stringvalidator.OneOf("elasticsearch", "observability", "security"),
 				},
 			},
+			"project_name": schema.StringAttribute{
+				Description: "Name of the serverless project the traffic filter is associated with",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cloud_provider": schema.StringAttribute{
+				Description: "Cloud provider hosting the serverless project, derived from the project's region: aws, azure or gcp",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"traffic_filter_id": schema.StringAttribute{
-				Description: "Required serverless traffic filter ID to associate with the project",
-				Required:    true,
+				Description: "Serverless traffic filter ID to associate with the project. Exactly one of traffic_filter_id or traffic_filter_name must be set",
+				Optional:    true,
+				Computed:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"traffic_filter_name": schema.StringAttribute{
+				Description: "Name of the serverless traffic filter to associate with the project. It's resolved to an ID in the project's region when the association is created, and must match exactly one traffic filter. Exactly one of traffic_filter_id or traffic_filter_name must be set",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("traffic_filter_id")),
+				},
+			},
+			"keep_default_filter_on_destroy": schema.BoolAttribute{
+				Description: "Keep the traffic filter associated with the project when destroying this resource, if the traffic filter is included by default in new projects, as these are managed by the platform. A warning is shown instead. Defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"timeouts": timeouts.Attributes(timeoutOpts),
 		},
 	}
 }
 
 type modelV0 struct {
-	ID              types.String `tfsdk:"id"`
-	ProjectID       types.String `tfsdk:"project_id"`
-	ProjectType     types.String `tfsdk:"project_type"`
-	TrafficFilterID types.String `tfsdk:"traffic_filter_id"`
+	ID                types.String `tfsdk:"id"`
+	CloudProvider     types.String `tfsdk:"cloud_provider"`
+	ProjectID         types.String `tfsdk:"project_id"`
+	ProjectName       types.String `tfsdk:"project_name"`
+	ProjectType       types.String `tfsdk:"project_type"`
+	TrafficFilterID   types.String `tfsdk:"traffic_filter_id"`
+	TrafficFilterName types.String `tfsdk:"traffic_filter_name"`
+	// KeepDefaultFilterOnDestroy is the only attribute which can be updated in place.
+	KeepDefaultFilterOnDestroy types.Bool   `tfsdk:"keep_default_filter_on_destroy"`
+	Timeouts                   types.Object `tfsdk:"timeouts"`
 }
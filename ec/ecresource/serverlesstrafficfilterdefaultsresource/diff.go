@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdefaultsresource
+
+// DiffFilterIDs returns the traffic filter IDs which are desired but not current,
+// and the ones which are current but not desired, in the order of their input.
+// Duplicate IDs are only returned once.
+func DiffFilterIDs(desired, current []string) (toAdd, toRemove []string) {
+	toAdd = difference(desired, current)
+	toRemove = difference(current, desired)
+	return toAdd, toRemove
+}
+
+// difference returns the IDs of a which aren't part of b.
+func difference(a, b []string) []string {
+	excluded := make(map[string]bool, len(a)+len(b))
+	for _, id := range b {
+		excluded[id] = true
+	}
+
+	result := []string{}
+	for _, id := range a {
+		if !excluded[id] {
+			result = append(result, id)
+			excluded[id] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,357 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdefaultsresource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/transport"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+var _ resource.Resource = &Resource{}
+var _ resource.ResourceWithConfigure = &Resource{}
+var _ resource.ResourceWithImportState = &Resource{}
+
+type Resource struct {
+	client             serverless.ClientWithResponsesInterface
+	mutationWindow     *util.MutationWindow
+	diagnosticsJSONLog bool
+}
+
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_serverless_traffic_filter_defaults"
+}
+
+func (r *Resource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	clients, diags := internal.ConvertProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	// Leave the resource unconfigured rather than partially configured.
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.client = clients.Serverless
+	r.mutationWindow = clients.MutationWindow
+	r.diagnosticsJSONLog = clients.DiagnosticsJSONLog
+}
+
+// logDiagnostics logs the diagnostics of an operation as JSON lines, if enabled
+// by the diagnostics_json_log provider attribute.
+func (r *Resource) logDiagnostics(ctx context.Context, diags diag.Diagnostics, failures *transport.Failures) {
+	if r.diagnosticsJSONLog {
+		transport.LogDiagnosticsJSON(ctx, diags, failures)
+	}
+}
+
+func resourceReady(r *Resource, dg *diag.Diagnostics) bool {
+	if r.client == nil {
+		dg.AddError(
+			"Unconfigured API Client",
+			"Expected configured API client. Please report this issue to the provider developers.",
+		)
+		return false
+	}
+	return true
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "update default traffic filters")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !resourceReady(r, &resp.Diagnostics) {
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &model, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	if !resourceReady(r, &resp.Diagnostics) {
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported resources only have their ID, which is the region.
+	if model.Region.IsNull() {
+		model.Region = model.ID
+	}
+
+	filters, diags := r.listFilters(ctx, model.Region.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ids []string
+	if model.TrafficFilterIDs.IsNull() {
+		// Take over all traffic filters currently included by default when importing.
+		for id, filter := range filters {
+			if filter.IncludeByDefault {
+				ids = append(ids, id)
+			}
+		}
+		model.ManagedTrafficFilterIDs, diags = setValue(ctx, ids)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		// Traffic filters deleted or no longer included by default show up as a diff.
+		for _, id := range stringElements(ctx, model.TrafficFilterIDs, &resp.Diagnostics) {
+			if filter, ok := filters[id]; ok && filter.IncludeByDefault {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	model.TrafficFilterIDs, diags = setValue(ctx, ids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "update default traffic filters")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !resourceReady(r, &resp.Diagnostics) {
+		return
+	}
+
+	var model, state modelV0
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := stringElements(ctx, state.ManagedTrafficFilterIDs, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &model, managed)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "update default traffic filters")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !resourceReady(r, &resp.Diagnostics) {
+		return
+	}
+
+	var model modelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := stringElements(ctx, model.ManagedTrafficFilterIDs, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Destroying the resource stops including all of its traffic filters by default.
+	model.TrafficFilterIDs = types.SetValueMust(types.StringType, nil)
+	resp.Diagnostics.Append(r.apply(ctx, &model, managed)...)
+}
+
+func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), resource.ImportStateRequest{ID: strings.TrimSpace(req.ID)}, resp)
+}
+
+// apply sets the include_by_default flag of the traffic filters listed in the model, and clears
+// it on the previously managed traffic filters which aren't listed anymore. The flag is only
+// patched on traffic filters where it differs.
+func (r *Resource) apply(ctx context.Context, model *modelV0, managed []string) diag.Diagnostics {
+	region := model.Region.ValueString()
+	var diags diag.Diagnostics
+	desired := stringElements(ctx, model.TrafficFilterIDs, &diags)
+	if diags.HasError() {
+		return diags
+	}
+
+	filters, d := r.listFilters(ctx, region)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	var current []string
+	for _, id := range desired {
+		filter, ok := filters[id]
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("traffic_filter_ids"),
+				"Traffic filter not found",
+				fmt.Sprintf("Traffic filter %s does not exist in region %s", id, region),
+			)
+			continue
+		}
+		if filter.IncludeByDefault {
+			current = append(current, id)
+		}
+	}
+	for _, id := range managed {
+		// Deleted traffic filters don't need to be updated.
+		if filter, ok := filters[id]; ok && filter.IncludeByDefault {
+			current = append(current, id)
+		}
+	}
+
+	toAdd, toRemove := DiffFilterIDs(desired, current)
+	for _, id := range toAdd {
+		if _, ok := filters[id]; ok {
+			diags.Append(r.setIncludeByDefault(ctx, id, true)...)
+		}
+	}
+	for _, id := range toRemove {
+		diags.Append(r.setIncludeByDefault(ctx, id, false)...)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	model.ID = model.Region
+	model.ManagedTrafficFilterIDs, d = setValue(ctx, desired)
+	diags.Append(d...)
+	return diags
+}
+
+// listFilters returns the traffic filters of the given region, keyed by ID.
+func (r *Resource) listFilters(ctx context.Context, region string) (map[string]serverless.TrafficFilterInfo, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	resp, err := r.client.ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{Region: &region})
+	if err != nil {
+		diags.AddError(util.APIListFailed, util.OperationDetail("list traffic filters", util.RequestErrorDetail(err)))
+		return nil, diags
+	}
+	if resp.JSON200 == nil {
+		diags.AddError(
+			util.APIListFailed,
+			util.OperationDetail("list traffic filters", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+		)
+		return nil, diags
+	}
+
+	filters := make(map[string]serverless.TrafficFilterInfo, len(resp.JSON200.Items))
+	for _, filter := range resp.JSON200.Items {
+		// Filter client side as well, so that the result doesn't depend on server side filtering.
+		if filter.Region == region {
+			filters[filter.Id] = filter
+		}
+	}
+	return filters, diags
+}
+
+func (r *Resource) setIncludeByDefault(ctx context.Context, id string, includeByDefault bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	resp, err := r.client.PatchTrafficFilterWithResponse(ctx, id, serverless.PatchTrafficFilterRequest{
+		IncludeByDefault: &includeByDefault,
+	})
+	if err != nil {
+		diags.AddError(util.APIUpdateFailed, util.OperationDetail("update traffic filter", util.RequestErrorDetail(err)))
+		return diags
+	}
+	if resp.JSON200 == nil {
+		diags.AddError(
+			util.APIUpdateFailed,
+			util.OperationDetail(fmt.Sprintf("set include_by_default to %t on traffic filter %s", includeByDefault, id),
+				util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+		)
+	}
+	return diags
+}
+
+func stringElements(ctx context.Context, set types.Set, diags *diag.Diagnostics) []string {
+	var result []string
+	if set.IsNull() || set.IsUnknown() {
+		return result
+	}
+	diags.Append(set.ElementsAs(ctx, &result, false)...)
+	return result
+}
+
+func setValue(ctx context.Context, ids []string) (types.Set, diag.Diagnostics) {
+	result := make([]string, len(ids))
+	copy(result, ids)
+	sort.Strings(result)
+	return types.SetValueFrom(ctx, types.StringType, result)
+}
@@ -0,0 +1,229 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdefaultsresource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func testSchema(t *testing.T) resource.SchemaResponse {
+	schemaResp := resource.SchemaResponse{}
+	(&Resource{}).Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	return schemaResp
+}
+
+func testModel(ids []string, managed []string) modelV0 {
+	model := modelV0{
+		ID:                      types.StringValue("us-east-1"),
+		Region:                  types.StringValue("us-east-1"),
+		TrafficFilterIDs:        types.SetValueMust(types.StringType, stringValues(ids)),
+		ManagedTrafficFilterIDs: types.SetUnknown(types.StringType),
+	}
+	if managed != nil {
+		model.ManagedTrafficFilterIDs = types.SetValueMust(types.StringType, stringValues(managed))
+	}
+	return model
+}
+
+func stringValues(values []string) []attr.Value {
+	result := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		result = append(result, types.StringValue(v))
+	}
+	return result
+}
+
+func expectFilters(mockClient *mocks.MockClientWithResponsesInterface, filters ...serverless.TrafficFilterInfo) {
+	region := "us-east-1"
+	mockClient.EXPECT().ListTrafficFiltersWithResponse(gomock.Any(), &serverless.ListTrafficFiltersParams{Region: &region}).Return(&serverless.ListTrafficFiltersResponse{
+		JSON200:      &serverless.TrafficFilterList{Items: filters},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+}
+
+func expectPatch(mockClient *mocks.MockClientWithResponsesInterface, id string, includeByDefault bool) {
+	mockClient.EXPECT().PatchTrafficFilterWithResponse(gomock.Any(), id, serverless.PatchTrafficFilterRequest{
+		IncludeByDefault: &includeByDefault,
+	}).Return(&serverless.PatchTrafficFilterResponse{
+		JSON200:      &serverless.TrafficFilterInfo{Id: id, Region: "us-east-1", IncludeByDefault: includeByDefault},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+}
+
+func filter(id string, includeByDefault bool) serverless.TrafficFilterInfo {
+	return serverless.TrafficFilterInfo{Id: id, Name: id, Region: "us-east-1", Type: "ip", IncludeByDefault: includeByDefault}
+}
+
+func stateIDs(t *testing.T, state tfsdk.State) (ids []string, managed []string) {
+	var model modelV0
+	require.False(t, state.Get(context.Background(), &model).HasError())
+	require.False(t, model.TrafficFilterIDs.ElementsAs(context.Background(), &ids, false).HasError())
+	require.False(t, model.ManagedTrafficFilterIDs.ElementsAs(context.Background(), &managed, false).HasError())
+	return ids, managed
+}
+
+func TestCreate_AddsDefaultStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectFilters(mockClient, filter("a", false), filter("b", true), filter("c", false))
+	// b is already included by default, c isn't listed.
+	expectPatch(mockClient, "a", true)
+
+	model := testModel([]string{"a", "b"}, nil)
+	model.ID = types.StringUnknown()
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, model, schemaResp.Schema.Type())}
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	ids, managed := stateIDs(t, resp.State)
+	require.Equal(t, []string{"a", "b"}, ids)
+	require.Equal(t, []string{"a", "b"}, managed)
+}
+
+func TestCreate_FailsForUnknownFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectFilters(mockClient, filter("a", false), serverless.TrafficFilterInfo{Id: "other-region", Region: "eu-west-1"})
+	expectPatch(mockClient, "a", true)
+
+	model := testModel([]string{"a", "other-region"}, nil)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, model, schemaResp.Schema.Type())}
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter not found", resp.Diagnostics.Errors()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "other-region does not exist in region us-east-1")
+	require.True(t, resp.State.Raw.IsNull())
+}
+
+func TestUpdate_RemovesDefaultStatusOfManagedFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	// c is included by default, but not managed by the resource.
+	expectFilters(mockClient, filter("a", true), filter("b", true), filter("c", true), filter("d", false))
+	expectPatch(mockClient, "b", false)
+	expectPatch(mockClient, "d", true)
+
+	state := testModel([]string{"a", "b"}, []string{"a", "b"})
+	plan := testModel([]string{"a", "d"}, nil)
+	req := resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, plan, schemaResp.Schema.Type())},
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, state, schemaResp.Schema.Type())},
+	}
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	(&Resource{client: mockClient}).Update(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	ids, managed := stateIDs(t, resp.State)
+	require.Equal(t, []string{"a", "d"}, ids)
+	require.Equal(t, []string{"a", "d"}, managed)
+}
+
+func TestDelete_RemovesDefaultStatusOfManagedFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	// b has been deleted, c isn't managed by the resource.
+	expectFilters(mockClient, filter("a", true), filter("c", true))
+	expectPatch(mockClient, "a", false)
+
+	state := testModel([]string{"a", "b"}, []string{"a", "b"})
+	req := resource.DeleteRequest{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: util.TfTypesValueFromGoTypeValue(t, state, schemaResp.Schema.Type())},
+	}
+	resp := resource.DeleteResponse{}
+	(&Resource{client: mockClient}).Delete(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+}
+
+func TestRead_ReportsFiltersNoLongerIncludedByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	// b stopped being included by default outside of Terraform, c has been deleted.
+	expectFilters(mockClient, filter("a", true), filter("b", false))
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    util.TfTypesValueFromGoTypeValue(t, testModel([]string{"a", "b", "c"}, []string{"a", "b", "c"}), schemaResp.Schema.Type()),
+	}
+	resp := resource.ReadResponse{State: state}
+	(&Resource{client: mockClient}).Read(ctx, resource.ReadRequest{State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	ids, managed := stateIDs(t, resp.State)
+	require.Equal(t, []string{"a"}, ids)
+	require.Equal(t, []string{"a", "b", "c"}, managed)
+}
+
+func TestImportState_ManagesCurrentDefaultFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectFilters(mockClient, filter("a", true), filter("b", false), filter("c", true))
+
+	r := &Resource{client: mockClient}
+	importResp := resource.ImportStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)},
+	}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: " us-east-1 "}, &importResp)
+	require.False(t, importResp.Diagnostics.HasError(), importResp.Diagnostics)
+
+	resp := resource.ReadResponse{State: importResp.State}
+	r.Read(ctx, resource.ReadRequest{State: importResp.State}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var model modelV0
+	require.False(t, resp.State.Get(ctx, &model).HasError())
+	require.Equal(t, "us-east-1", model.Region.ValueString())
+	ids, managed := stateIDs(t, resp.State)
+	require.Equal(t, []string{"a", "c"}, ids)
+	require.Equal(t, []string{"a", "c"}, managed)
+}
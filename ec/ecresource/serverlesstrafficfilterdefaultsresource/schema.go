@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterdefaultsresource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Provides an Elastic Cloud serverless traffic filter defaults resource, which manages which traffic filters of a region are automatically included in new projects. The include_by_default flag of the listed traffic filters is set, and cleared again on traffic filters which are removed from the list. Traffic filters which have never been listed are left untouched.
+
+~> **Note on include_by_default** Do not set the ` + "`include_by_default`" + ` attribute of ` + "`ec_serverless_traffic_filter`" + ` resources for traffic filters managed by this resource, as both resources would overwrite each other's changes.`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Unique identifier of this resource, the region.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"region": schema.StringAttribute{
+				Description: "Region of the traffic filters",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"traffic_filter_ids": schema.SetAttribute{
+				Description: "IDs of the traffic filters of the region which are included in new projects by default",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"managed_traffic_filter_ids": schema.SetAttribute{
+				Description: "IDs of the traffic filters whose include_by_default flag is managed by this resource. Traffic filters which are removed from traffic_filter_ids stop being included by default, other traffic filters of the region are left untouched",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type modelV0 struct {
+	ID                      types.String `tfsdk:"id"`
+	Region                  types.String `tfsdk:"region"`
+	TrafficFilterIDs        types.Set    `tfsdk:"traffic_filter_ids"`
+	ManagedTrafficFilterIDs types.Set    `tfsdk:"managed_traffic_filter_ids"`
+}
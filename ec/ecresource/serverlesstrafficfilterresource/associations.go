@@ -0,0 +1,177 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+)
+
+type associatedProject struct {
+	ID   string
+	Name string
+	Type string
+}
+
+func (p associatedProject) String() string {
+	return fmt.Sprintf("%s (%s project %s)", p.Name, p.Type, p.ID)
+}
+
+// findAssociatedProjects scans the projects of all types for the ones the given traffic filter is associated with.
+func (r *Resource) findAssociatedProjects(ctx context.Context, filterID string) ([]associatedProject, error) {
+	var result []associatedProject
+	collect := func(projectType, id, name string, filters *serverless.TrafficFilters) {
+		if filters == nil {
+			return
+		}
+		for _, f := range *filters {
+			if f.Id == filterID {
+				result = append(result, associatedProject{ID: id, Name: name, Type: projectType})
+				return
+			}
+		}
+	}
+
+	var nextPage *string
+	for {
+		resp, err := r.client.ListElasticsearchProjectsWithResponse(ctx, &serverless.ListElasticsearchProjectsParams{NextPage: nextPage})
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("listing elasticsearch projects failed with: %d %s", resp.StatusCode(), resp.Status())
+		}
+		for _, p := range resp.JSON200.Items {
+			collect("elasticsearch", p.Id, p.Name, p.TrafficFilters)
+		}
+		if nextPage = resp.JSON200.NextPage; nextPage == nil || *nextPage == "" {
+			break
+		}
+	}
+
+	nextPage = nil
+	for {
+		resp, err := r.client.ListObservabilityProjectsWithResponse(ctx, &serverless.ListObservabilityProjectsParams{NextPage: nextPage})
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("listing observability projects failed with: %d %s", resp.StatusCode(), resp.Status())
+		}
+		for _, p := range resp.JSON200.Items {
+			collect("observability", p.Id, p.Name, p.TrafficFilters)
+		}
+		if nextPage = resp.JSON200.NextPage; nextPage == nil || *nextPage == "" {
+			break
+		}
+	}
+
+	nextPage = nil
+	for {
+		resp, err := r.client.ListSecurityProjectsWithResponse(ctx, &serverless.ListSecurityProjectsParams{NextPage: nextPage})
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("listing security projects failed with: %d %s", resp.StatusCode(), resp.Status())
+		}
+		for _, p := range resp.JSON200.Items {
+			collect("security", p.Id, p.Name, p.TrafficFilters)
+		}
+		if nextPage = resp.JSON200.NextPage; nextPage == nil || *nextPage == "" {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// reportedAssociationCount returns the association count included in a traffic filter response body.
+// It isn't part of the documented traffic filter info, so it's only used if the API reports it.
+func reportedAssociationCount(body []byte) (int64, bool) {
+	var info struct {
+		AssociationCount *int64 `json:"association_count"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil || info.AssociationCount == nil {
+		return 0, false
+	}
+	return *info.AssociationCount, true
+}
+
+// associationCount returns the number of projects the traffic filter is associated with. The count
+// reported in the response body is preferred. Only if there is none all projects of all types are
+// listed, which takes several requests in large organizations, so it's only done on create, update
+// and import rather than on every read.
+func (r *Resource) associationCount(ctx context.Context, filterID string, body []byte) (types.Int64, diag.Diagnostics) {
+	if count, ok := reportedAssociationCount(body); ok {
+		return types.Int64Value(count), nil
+	}
+
+	var diags diag.Diagnostics
+	projects, err := r.findAssociatedProjects(ctx, filterID)
+	if err != nil {
+		diags.AddWarning(
+			"Failed to count traffic filter associations",
+			fmt.Sprintf("The projects associated with traffic filter %s could not be determined: %s", filterID, err),
+		)
+		return types.Int64Null(), diags
+	}
+	return types.Int64Value(int64(len(projects))), diags
+}
+
+// warnAboutAssociations adds a warning listing the projects which will lose their association with the traffic filter.
+// If the associations can't be determined, e.g. since the API isn't reachable at plan time, a generic warning is added instead.
+func (r *Resource) warnAboutAssociations(ctx context.Context, filterID string, action string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	summary := "Traffic filter associations will be removed"
+
+	if r.client == nil {
+		diags.AddWarning(summary, fmt.Sprintf("Traffic filter %s will be %s. Any projects associated with it will lose this association.", filterID, action))
+		return diags
+	}
+
+	projects, err := r.findAssociatedProjects(ctx, filterID)
+	if err != nil {
+		diags.AddWarning(summary, fmt.Sprintf(
+			"Traffic filter %s will be %s. Any projects associated with it will lose this association.\n\nThe associated projects could not be determined: %s",
+			filterID, action, err,
+		))
+		return diags
+	}
+
+	if len(projects) == 0 {
+		return diags
+	}
+
+	names := make([]string, 0, len(projects))
+	for _, p := range projects {
+		names = append(names, p.String())
+	}
+	diags.AddWarning(summary, fmt.Sprintf(
+		"Traffic filter %s will be %s. The following projects will lose their association with it:\n  - %s",
+		filterID, action, strings.Join(names, "\n  - "),
+	))
+	return diags
+}
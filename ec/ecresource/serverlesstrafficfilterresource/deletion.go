@@ -0,0 +1,105 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+const (
+	// deletionPollInterval is the time between checks whether a deleted traffic filter is gone.
+	deletionPollInterval = 5 * time.Second
+	// defaultDeletionTimeout limits how long to wait for a deleted traffic filter to be gone,
+	// unless the delete timeout is configured.
+	defaultDeletionTimeout = 5 * time.Minute
+)
+
+// waitForDeletion polls the traffic filter until the API doesn't return it anymore. The API may
+// acknowledge the deletion before the traffic filter is fully removed, in which case creating a
+// traffic filter with the same name right away can conflict with it.
+func (r *Resource) waitForDeletion(ctx context.Context, id string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var waited time.Duration
+	for {
+		getResp, err := r.client.GetTrafficFilterWithResponse(ctx, id)
+		if err != nil {
+			diags.AddError(util.APIReadFailed, util.OperationDetail("read traffic filter", util.RequestErrorDetail(err)))
+			return diags
+		}
+		if getResp.StatusCode() == http.StatusNotFound {
+			return diags
+		}
+		if getResp.JSON200 == nil {
+			diags.AddError(
+				util.APIReadFailed,
+				util.OperationDetail("read traffic filter", util.APIFailureDetail(getResp.HTTPResponse, getResp.Body)),
+			)
+			return diags
+		}
+
+		if waited >= timeout {
+			diags.AddError(
+				"Traffic filter not deleted",
+				fmt.Sprintf("Traffic filter %s has been deleted, but is still returned by the API after waiting %s for it to be removed. Please retry the operation later.", id, waited),
+			)
+			return diags
+		}
+		if err := r.wait(ctx, deletionPollInterval); err != nil {
+			diags.AddError(
+				"Traffic filter not deleted",
+				fmt.Sprintf("Traffic filter %s has been deleted, but stopped waiting for it to be removed: %s", id, err),
+			)
+			return diags
+		}
+		waited += deletionPollInterval
+	}
+}
+
+// isAsyncDeletion reports whether the API acknowledged the deletion with a body such as
+// {"status":"deleting"}, telling that the traffic filter is only removed asynchronously.
+func isAsyncDeletion(statusCode int, body []byte) bool {
+	if statusCode != http.StatusOK || len(body) == 0 {
+		return false
+	}
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return false
+	}
+	return strings.EqualFold(status.Status, "deleting")
+}
+
+// wait waits for the given duration, unless the context is done before.
+func (r *Resource) wait(ctx context.Context, d time.Duration) error {
+	if r.sleep == nil {
+		return timeouts.Sleep(ctx, d)
+	}
+	r.sleep(d)
+	return ctx.Err()
+}
@@ -0,0 +1,167 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+)
+
+func deleteResource(t *testing.T, r *Resource, waitForDeletion bool) resource.DeleteResponse {
+	return deleteResourceWithContext(context.Background(), t, r, testModel(), waitForDeletion)
+}
+
+func deleteResourceWithContext(ctx context.Context, t *testing.T, r *Resource, model TrafficFilterModel, waitForDeletion bool) resource.DeleteResponse {
+	model.ID = types.StringValue("filter-id")
+	model.WaitForDeletion = types.BoolValue(waitForDeletion)
+
+	resp := resource.DeleteResponse{}
+	r.Delete(ctx, resource.DeleteRequest{State: testState(t, model)}, &resp)
+	return resp
+}
+
+func expectDelete(mockClient *mocks.MockClientWithResponsesInterface) *gomock.Call {
+	return mockClient.EXPECT().DeleteTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.DeleteTrafficFilterResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+}
+
+func expectGet(mockClient *mocks.MockClientWithResponsesInterface, statusCode int) *gomock.Call {
+	getResp := &serverless.GetTrafficFilterResponse{HTTPResponse: &http.Response{StatusCode: statusCode}}
+	if statusCode == http.StatusOK {
+		getResp.JSON200 = &serverless.TrafficFilterInfo{Id: "filter-id"}
+	}
+	return mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(getResp, nil)
+}
+
+func TestDelete_WaitsForDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	gomock.InOrder(
+		expectDelete(mockClient),
+		expectGet(mockClient, http.StatusOK),
+		expectGet(mockClient, http.StatusNotFound),
+	)
+
+	var slept []time.Duration
+	r := &Resource{client: mockClient, sleep: func(d time.Duration) { slept = append(slept, d) }}
+	resp := deleteResource(t, r, true)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, []time.Duration{deletionPollInterval}, slept)
+}
+
+func TestDelete_GivesUpWaitingForDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectDelete(mockClient)
+	polls := int(defaultDeletionTimeout/deletionPollInterval) + 1
+	expectGet(mockClient, http.StatusOK).Times(polls)
+
+	r := &Resource{client: mockClient, sleep: func(time.Duration) {}}
+	resp := deleteResource(t, r, true)
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter not deleted", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func TestDelete_UsesConfiguredTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectDelete(mockClient)
+	expectGet(mockClient, http.StatusOK).Times(3)
+
+	model := testModel()
+	model.Timeouts = types.ObjectValueMust(timeouts.AttrTypes(timeoutOpts), map[string]attr.Value{
+		"delete": types.StringValue("10s"),
+	})
+
+	r := &Resource{client: mockClient, sleep: func(time.Duration) {}}
+	resp := deleteResourceWithContext(context.Background(), t, r, model, true)
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter not deleted", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func TestDelete_StopsWaitingWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectDelete(mockClient)
+	expectGet(mockClient, http.StatusOK)
+
+	r := &Resource{client: mockClient, sleep: func(time.Duration) { cancel() }}
+	resp := deleteResourceWithContext(ctx, t, r, testModel(), true)
+	require.True(t, resp.Diagnostics.HasError())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), context.Canceled.Error())
+}
+
+func TestDelete_DoesNotWaitByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectDelete(mockClient)
+
+	r := &Resource{client: mockClient, sleep: func(time.Duration) { t.Fatal("unexpected wait") }}
+	resp := deleteResource(t, r, false)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+}
+
+func TestDelete_WaitsForAsyncDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	gomock.InOrder(
+		mockClient.EXPECT().DeleteTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.DeleteTrafficFilterResponse{
+			Body:         []byte(`{"status":"deleting"}`),
+			HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		}, nil),
+		expectGet(mockClient, http.StatusOK),
+		expectGet(mockClient, http.StatusOK),
+		expectGet(mockClient, http.StatusNotFound),
+	)
+
+	var slept []time.Duration
+	r := &Resource{client: mockClient, sleep: func(d time.Duration) { slept = append(slept, d) }}
+	resp := deleteResource(t, r, false)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Equal(t, []time.Duration{deletionPollInterval, deletionPollInterval}, slept)
+}
+
+func TestIsAsyncDeletion(t *testing.T) {
+	require.True(t, isAsyncDeletion(http.StatusOK, []byte(`{"status":"deleting"}`)))
+	require.True(t, isAsyncDeletion(http.StatusOK, []byte(`{"status":"DELETING","id":"filter-id"}`)))
+	require.False(t, isAsyncDeletion(http.StatusOK, []byte(`{"status":"deleted"}`)))
+	require.False(t, isAsyncDeletion(http.StatusOK, []byte(`not json`)))
+	require.False(t, isAsyncDeletion(http.StatusOK, nil))
+	require.False(t, isAsyncDeletion(http.StatusNoContent, []byte(`{"status":"deleting"}`)))
+}
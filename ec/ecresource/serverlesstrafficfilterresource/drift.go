@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const lastAppliedRulesKey = "last_applied_rules"
+
+// privateState is satisfied by the framework's resource private state.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+type appliedRule struct {
+	Source      string `json:"source"`
+	Description string `json:"description,omitempty"`
+}
+
+func (r appliedRule) String() string {
+	if r.Description == "" {
+		return r.Source
+	}
+	return fmt.Sprintf("%s (%s)", r.Source, r.Description)
+}
+
+func appliedRulesFromModel(rules []TrafficFilterRuleModel) []appliedRule {
+	result := make([]appliedRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, appliedRule{
+			Source:      rule.Source.ValueString(),
+			Description: rule.Description.ValueString(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Source != result[j].Source {
+			return result[i].Source < result[j].Source
+		}
+		return result[i].Description < result[j].Description
+	})
+	return result
+}
+
+// setLastAppliedRules stores a snapshot of the given rules in the private state.
+func setLastAppliedRules(ctx context.Context, private privateState, rules []TrafficFilterRuleModel) diag.Diagnostics {
+	snapshot, err := json.Marshal(appliedRulesFromModel(rules))
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to store applied traffic filter rules", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, lastAppliedRulesKey, snapshot)
+}
+
+// checkRulesDrift compares the remote rules with the snapshot stored in the
+// private state and warns about any rules added or removed outside of Terraform.
+func checkRulesDrift(ctx context.Context, private privateState, id string, remote []TrafficFilterRuleModel) diag.Diagnostics {
+	snapshot, diags := private.GetKey(ctx, lastAppliedRulesKey)
+	if diags.HasError() || snapshot == nil {
+		return diags
+	}
+
+	var lastApplied []appliedRule
+	if err := json.Unmarshal(snapshot, &lastApplied); err != nil {
+		// The snapshot is only used for reporting, an unreadable one is replaced on the next write.
+		return diags
+	}
+
+	added, removed := diffAppliedRules(lastApplied, appliedRulesFromModel(remote))
+	if len(added) == 0 && len(removed) == 0 {
+		return diags
+	}
+
+	var detail strings.Builder
+	fmt.Fprintf(&detail, "The rules of traffic filter %s have been changed outside of Terraform.", id)
+	if len(added) > 0 {
+		fmt.Fprintf(&detail, "\n\nAdded rules:\n  - %s", strings.Join(added, "\n  - "))
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(&detail, "\n\nRemoved rules:\n  - %s", strings.Join(removed, "\n  - "))
+	}
+	diags.AddWarning("Traffic filter rules changed outside of Terraform", detail.String())
+	return diags
+}
+
+func diffAppliedRules(lastApplied, remote []appliedRule) (added []string, removed []string) {
+	lastAppliedSet := make(map[appliedRule]bool, len(lastApplied))
+	for _, rule := range lastApplied {
+		lastAppliedSet[rule] = true
+	}
+	remoteSet := make(map[appliedRule]bool, len(remote))
+	for _, rule := range remote {
+		remoteSet[rule] = true
+		if !lastAppliedSet[rule] {
+			added = append(added, rule.String())
+		}
+	}
+	for _, rule := range lastApplied {
+		if !remoteSet[rule] {
+			removed = append(removed, rule.String())
+		}
+	}
+	return added, removed
+}
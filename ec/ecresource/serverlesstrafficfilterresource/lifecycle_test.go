@@ -0,0 +1,189 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	provider "github.com/elastic/terraform-provider-ec/ec"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+)
+
+func TestLifecycle_RemovingDescriptionClearsIt(t *testing.T) {
+	fake := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: fake.providerFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: filterConfig(`description = "office network"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ec_serverless_traffic_filter.test", "description", "office network"),
+					func(_ *terraform.State) error {
+						require.Equal(t, "office network", fake.description())
+						return nil
+					},
+				),
+			},
+			{
+				Config: filterConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ec_serverless_traffic_filter.test", "description"),
+					func(_ *terraform.State) error {
+						require.Equal(t, "", fake.description())
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func filterConfig(description string) string {
+	return `
+resource "ec_serverless_traffic_filter" "test" {
+  name   = "my-filter"
+  type   = "ip"
+  region = "us-east-1"
+  ` + description + `
+
+  rule {
+    source = "1.1.1.1"
+  }
+}
+`
+}
+
+// fakeAPI serves a single traffic filter from memory.
+type fakeAPI struct {
+	mu     sync.Mutex
+	filter *serverless.TrafficFilterInfo
+	client *mocks.MockClientWithResponsesInterface
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	f := &fakeAPI{client: mocks.NewMockClientWithResponsesInterface(gomock.NewController(t))}
+
+	f.client.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.filter = &serverless.TrafficFilterInfo{
+				Id:          "filter-id",
+				Name:        body.Name,
+				Region:      body.Region,
+				Type:        body.Type,
+				Description: body.Description,
+				Rules:       *body.Rules,
+			}
+			info, raw := f.response()
+			return &serverless.CreateTrafficFilterResponse{JSON201: info, Body: raw, HTTPResponse: &http.Response{StatusCode: http.StatusCreated}}, nil
+		}).AnyTimes()
+
+	f.client.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ ...serverless.RequestEditorFn) (*serverless.GetTrafficFilterResponse, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if f.filter == nil {
+				return &serverless.GetTrafficFilterResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil
+			}
+			info, raw := f.response()
+			return &serverless.GetTrafficFilterResponse{JSON200: info, Body: raw, HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+		}).AnyTimes()
+
+	f.client.EXPECT().PatchTrafficFilterWithResponse(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, body serverless.PatchTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.PatchTrafficFilterResponse, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if body.Name != nil {
+				f.filter.Name = *body.Name
+			}
+			if body.Description != nil {
+				f.filter.Description = body.Description
+			}
+			if body.IncludeByDefault != nil {
+				f.filter.IncludeByDefault = *body.IncludeByDefault
+			}
+			if body.Rules != nil {
+				f.filter.Rules = *body.Rules
+			}
+			info, raw := f.response()
+			return &serverless.PatchTrafficFilterResponse{JSON200: info, Body: raw, HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+		}).AnyTimes()
+
+	f.client.EXPECT().DeleteTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ ...serverless.RequestEditorFn) (*serverless.DeleteTrafficFilterResponse, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.filter = nil
+			return &serverless.DeleteTrafficFilterResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+		}).AnyTimes()
+
+	// Destroying the traffic filter looks up the projects associated with it.
+	f.client.EXPECT().ListElasticsearchProjectsWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.ListElasticsearchProjectsResponse{
+		JSON200: &serverless.ElasticsearchProjectList{}, HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).AnyTimes()
+	f.client.EXPECT().ListObservabilityProjectsWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.ListObservabilityProjectsResponse{
+		JSON200: &serverless.ObservabilityProjectList{}, HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).AnyTimes()
+	f.client.EXPECT().ListSecurityProjectsWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.ListSecurityProjectsResponse{
+		JSON200: &serverless.SecurityProjectList{}, HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).AnyTimes()
+
+	return f
+}
+
+// response returns a copy of the traffic filter along with its JSON body, which reports the association count.
+func (f *fakeAPI) response() (*serverless.TrafficFilterInfo, []byte) {
+	info := *f.filter
+	body, _ := json.Marshal(struct {
+		serverless.TrafficFilterInfo
+		AssociationCount int `json:"association_count"`
+	}{TrafficFilterInfo: info})
+	return &info, body
+}
+
+func (f *fakeAPI) description() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.filter == nil || f.filter.Description == nil {
+		return ""
+	}
+	return *f.filter.Description
+}
+
+func (f *fakeAPI) providerFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"ec": func() (tfprotov6.ProviderServer, error) {
+			return providerserver.NewProtocol6(provider.ProviderWithClients(api.NewMock(), f.client, "unit-tests"))(), nil
+		},
+	}
+}
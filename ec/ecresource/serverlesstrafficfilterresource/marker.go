@@ -0,0 +1,153 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// managedMarker is appended to the description of traffic filters with
+// manage_marker set, to show in the UI that they're managed by Terraform.
+const managedMarker = "[terraform]"
+
+// serverDefaultDescriptionPrefix starts the description the API applies to
+// traffic filters created without one, e.g. "Created by jane@example.com".
+const serverDefaultDescriptionPrefix = "Created by "
+
+// ignoredDescriptionKey stores in the private state the description the API
+// applied to a traffic filter created without one.
+const ignoredDescriptionKey = "ignored_description"
+
+// apiDescription returns the description sent to the API, including the
+// marker if the model has manage_marker set.
+func apiDescription(model TrafficFilterModel) *string {
+	if !model.ManageMarker.ValueBool() {
+		return optionalString(model.Description)
+	}
+
+	description := managedMarker
+	if d := model.Description.ValueString(); d != "" {
+		description = d + " " + managedMarker
+	}
+	return &description
+}
+
+// withoutManagedMarker removes the marker from a description read from the API,
+// reporting whether it was there.
+func withoutManagedMarker(description string) (string, bool) {
+	stripped, found := strings.CutSuffix(description, managedMarker)
+	if !found {
+		return description, false
+	}
+	return strings.TrimSuffix(stripped, " "), true
+}
+
+// isServerDefaultDescription reports whether a description read from the API
+// was applied by the API because none was provided.
+func isServerDefaultDescription(description string) bool {
+	return strings.HasPrefix(description, serverDefaultDescriptionPrefix)
+}
+
+// descriptionFromResponse returns the description and manage_marker of the model read from the
+// API. Without a prior value, e.g. when importing, manage_marker is set if the marker is present.
+func descriptionFromResponse(description *string, prior TrafficFilterModel) (types.String, types.Bool) {
+	value := ""
+	if description != nil {
+		value = *description
+	}
+
+	manageMarker := prior.ManageMarker
+	if manageMarker.IsNull() || manageMarker.IsUnknown() {
+		_, found := withoutManagedMarker(value)
+		manageMarker = boolValue(found)
+	}
+	if manageMarker.ValueBool() {
+		value, _ = withoutManagedMarker(value)
+	}
+
+	if value == "" {
+		return types.StringNull(), manageMarker
+	}
+	return stringValue(value), manageMarker
+}
+
+// ignoreServerDefaultDescription treats the description the API applies to traffic filters created
+// without one as unset, so that it doesn't show up as a diff. It's only called right after the
+// creation, the ignored description is stored in the private state to keep ignoring it later on.
+func ignoreServerDefaultDescription(ctx context.Context, private privateState, model *TrafficFilterModel, plan TrafficFilterModel) diag.Diagnostics {
+	if !plan.Description.IsNull() || !isServerDefaultDescription(model.Description.ValueString()) {
+		return nil
+	}
+
+	value, err := json.Marshal(model.Description.ValueString())
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to store ignored traffic filter description", err.Error())
+		return diags
+	}
+	model.Description = types.StringNull()
+	return private.SetKey(ctx, ignoredDescriptionKey, value)
+}
+
+// withoutIgnoredDescription keeps the description ignored on creation unset as long as the prior
+// description is unset. Descriptions of imported traffic filters are kept as read from the API.
+func withoutIgnoredDescription(ctx context.Context, private privateState, model *TrafficFilterModel, prior TrafficFilterModel) diag.Diagnostics {
+	if !prior.Description.IsNull() || model.Description.IsNull() {
+		return nil
+	}
+
+	value, diags := private.GetKey(ctx, ignoredDescriptionKey)
+	if diags.HasError() || value == nil {
+		return diags
+	}
+
+	var ignored string
+	if err := json.Unmarshal(value, &ignored); err != nil {
+		// An unreadable value is treated as if nothing was ignored.
+		return diags
+	}
+	if model.Description.ValueString() == ignored {
+		model.Description = types.StringNull()
+	}
+	return diags
+}
+
+// planDescription plans the description of traffic filters without one in their configuration:
+// the default_filter_description of the provider if set, otherwise none. The attribute is computed
+// so that the default shows up in the plan and the state. Terraform proposes the prior description
+// when it's removed from the configuration, so it's replaced here as well to clear it.
+func (r *Resource) planDescription(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("description"), &configured)...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	description := types.StringNull()
+	if r.defaultDescription != "" {
+		description = stringValue(r.defaultDescription)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("description"), description)...)
+}
@@ -0,0 +1,369 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func TestApiDescription(t *testing.T) {
+	tests := []struct {
+		name         string
+		description  types.String
+		manageMarker types.Bool
+		expected     *string
+	}{
+		{name: "without marker", description: types.StringValue("office"), manageMarker: types.BoolValue(false), expected: ec.String("office")},
+		{name: "appends the marker", description: types.StringValue("office"), manageMarker: types.BoolValue(true), expected: ec.String("office [terraform]")},
+		{name: "marker only without description", description: types.StringNull(), manageMarker: types.BoolValue(true), expected: ec.String("[terraform]")},
+		{name: "no description without marker", description: types.StringNull(), manageMarker: types.BoolValue(false), expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := testModel()
+			model.Description = tt.description
+			model.ManageMarker = tt.manageMarker
+			require.Equal(t, tt.expected, apiDescription(model))
+		})
+	}
+}
+
+func TestDescriptionFromResponse(t *testing.T) {
+	tests := []struct {
+		name                 string
+		description          *string
+		priorDescription     types.String
+		priorManageMarker    types.Bool
+		expectedDescription  types.String
+		expectedManageMarker types.Bool
+	}{
+		{
+			name:                 "strips the marker",
+			description:          ec.String("office [terraform]"),
+			priorManageMarker:    types.BoolValue(true),
+			expectedDescription:  types.StringValue("office"),
+			expectedManageMarker: types.BoolValue(true),
+		},
+		{
+			name:                 "marker only",
+			description:          ec.String("[terraform]"),
+			priorManageMarker:    types.BoolValue(true),
+			expectedDescription:  types.StringNull(),
+			expectedManageMarker: types.BoolValue(true),
+		},
+		{
+			name:                 "keeps the description as is without manage_marker",
+			description:          ec.String("office [terraform]"),
+			priorManageMarker:    types.BoolValue(false),
+			expectedDescription:  types.StringValue("office [terraform]"),
+			expectedManageMarker: types.BoolValue(false),
+		},
+		{
+			name:                 "detects the marker when importing",
+			description:          ec.String("office [terraform]"),
+			priorManageMarker:    types.BoolNull(),
+			expectedDescription:  types.StringValue("office"),
+			expectedManageMarker: types.BoolValue(true),
+		},
+		{
+			name:                 "no marker when importing",
+			description:          ec.String("office"),
+			priorManageMarker:    types.BoolNull(),
+			expectedDescription:  types.StringValue("office"),
+			expectedManageMarker: types.BoolValue(false),
+		},
+		{
+			name:                 "keeps a description looking like the one applied by the API",
+			description:          ec.String("Created by the network team"),
+			priorDescription:     types.StringNull(),
+			priorManageMarker:    types.BoolNull(),
+			expectedDescription:  types.StringValue("Created by the network team"),
+			expectedManageMarker: types.BoolValue(false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prior := testModel()
+			prior.Description = tt.priorDescription
+			prior.ManageMarker = tt.priorManageMarker
+			description, manageMarker := descriptionFromResponse(tt.description, prior)
+			require.Equal(t, tt.expectedDescription, description)
+			require.Equal(t, tt.expectedManageMarker, manageMarker)
+		})
+	}
+}
+
+func TestCreate_AppendsManagedMarker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+			require.Equal(t, ec.String("office [terraform]"), body.Description)
+			return &serverless.CreateTrafficFilterResponse{
+				JSON201: &serverless.TrafficFilterInfo{
+					Id:          "filter-id",
+					Name:        "my-filter",
+					Region:      "us-east-1",
+					Type:        "ip",
+					Description: body.Description,
+					Rules:       []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+				},
+				HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+			}, nil
+		})
+
+	model := testModel()
+	model.Description = types.StringValue("office")
+	model.ManageMarker = types.BoolValue(true)
+
+	plan := testPlan(t, model)
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, types.StringValue("office"), state.Description)
+	require.Equal(t, types.BoolValue(true), state.ManageMarker)
+}
+
+func TestUpdate_ClearsManagedMarker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	stateModel := testModel()
+	stateModel.ID = types.StringValue("filter-id")
+	stateModel.ManageMarker = types.BoolValue(true)
+	planModel := stateModel
+	planModel.ManageMarker = types.BoolValue(false)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().PatchTrafficFilterWithResponse(gomock.Any(), "filter-id", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, body serverless.PatchTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.PatchTrafficFilterResponse, error) {
+			require.Equal(t, ec.String(""), body.Description)
+			return &serverless.PatchTrafficFilterResponse{
+				HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+			}, nil
+		})
+
+	plan := testPlan(t, planModel)
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	(&Resource{client: mockClient}).Update(context.Background(), resource.UpdateRequest{Plan: plan, State: testState(t, stateModel)}, &resp)
+	require.Equal(t, util.APIUpdateFailed, resp.Diagnostics[0].Summary())
+}
+
+func TestCreate_IgnoresServerDefaultDescription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		JSON201:      testFilterInfo("Created by jane@example.com"),
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+	}, nil)
+
+	model := testModel()
+	model.ManageMarker = types.BoolValue(false)
+
+	plan := testPlan(t, model)
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.True(t, state.Description.IsNull())
+
+	ignored, diags := resp.Private.GetKey(ctx, ignoredDescriptionKey)
+	require.False(t, diags.HasError(), diags)
+	require.JSONEq(t, `"Created by jane@example.com"`, string(ignored))
+}
+
+func TestRead_IgnoresServerDefaultDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		ignored     string
+		description string
+		expected    types.String
+	}{
+		{
+			name:        "keeps ignoring the description applied on creation",
+			ignored:     "Created by jane@example.com",
+			description: "Created by jane@example.com",
+			expected:    types.StringNull(),
+		},
+		{
+			name:        "reads a description changed outside of Terraform",
+			ignored:     "Created by jane@example.com",
+			description: "Created by the network team",
+			expected:    types.StringValue("Created by the network team"),
+		},
+		{
+			name:        "keeps the description of imported traffic filters",
+			description: "Created by the network team",
+			expected:    types.StringValue("Created by the network team"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			model := testModel()
+			model.ID = types.StringValue("filter-id")
+			model.ManageMarker = types.BoolValue(false)
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+				JSON200:      testFilterInfo(tt.description),
+				Body:         []byte(`{"association_count":0}`),
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+
+			state := testState(t, model)
+			req := resource.ReadRequest{State: state}
+			initPrivateState(t, &req)
+			if tt.ignored != "" {
+				ignored, err := json.Marshal(tt.ignored)
+				require.NoError(t, err)
+				require.False(t, req.Private.SetKey(ctx, ignoredDescriptionKey, ignored).HasError())
+			}
+			resp := resource.ReadResponse{State: state}
+			initPrivateState(t, &resp)
+			(&Resource{client: mockClient}).Read(ctx, req, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var newState TrafficFilterModel
+			require.False(t, resp.State.Get(ctx, &newState).HasError())
+			require.Equal(t, tt.expected, newState.Description)
+		})
+	}
+}
+
+func testFilterInfo(description string) *serverless.TrafficFilterInfo {
+	return &serverless.TrafficFilterInfo{
+		Id:          "filter-id",
+		Name:        "my-filter",
+		Region:      "us-east-1",
+		Type:        "ip",
+		Description: ec.String(description),
+		Rules:       []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+	}
+}
+
+func TestModifyPlan_DefaultDescription(t *testing.T) {
+	tests := []struct {
+		name               string
+		defaultDescription string
+		prior              *types.String
+		description        types.String
+		expected           types.String
+	}{
+		{
+			name:               "uses the default if the description is unset",
+			defaultDescription: "Managed by Terraform",
+			description:        types.StringNull(),
+			expected:           types.StringValue("Managed by Terraform"),
+		},
+		{
+			name:               "keeps the configured description",
+			defaultDescription: "Managed by Terraform",
+			description:        types.StringValue("office network"),
+			expected:           types.StringValue("office network"),
+		},
+		{
+			name:        "plans no description without a default",
+			description: types.StringNull(),
+			expected:    types.StringNull(),
+		},
+		{
+			name:        "clears a description removed from the configuration",
+			prior:       ptr(types.StringValue("office network")),
+			description: types.StringNull(),
+			expected:    types.StringNull(),
+		},
+		{
+			name:               "replaces a removed description by the default",
+			defaultDescription: "Managed by Terraform",
+			prior:              ptr(types.StringValue("office network")),
+			description:        types.StringNull(),
+			expected:           types.StringValue("Managed by Terraform"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			configModel := testModel()
+			configModel.Description = tt.description
+
+			// Terraform proposes the prior value of computed attributes which aren't configured.
+			planModel := configModel
+			state := tfsdk.State{Schema: testSchema(t).Schema, Raw: tftypes.NewValue(testSchema(t).Schema.Type().TerraformType(ctx), nil)}
+			if tt.prior != nil {
+				priorModel := configModel
+				priorModel.ID = types.StringValue("filter-id")
+				priorModel.Description = *tt.prior
+				state = testState(t, priorModel)
+				planModel.ID = priorModel.ID
+				if tt.description.IsNull() {
+					planModel.Description = *tt.prior
+				}
+			} else if tt.description.IsNull() {
+				planModel.Description = types.StringUnknown()
+			}
+
+			plan := testPlan(t, planModel)
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			(&Resource{defaultDescription: tt.defaultDescription}).ModifyPlan(ctx, resource.ModifyPlanRequest{
+				Config: testConfig(t, configModel),
+				Plan:   plan,
+				State:  state,
+			}, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var planned TrafficFilterModel
+			require.False(t, resp.Plan.Get(ctx, &planned).HasError())
+			require.Equal(t, tt.expected, planned.Description)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// minRules prevents a plan from shrinking the rules of an existing traffic
+// filter below the configured minimum. Unlike a static size validator, it
+// still allows traffic filters which already have fewer rules to be updated,
+// as long as the update doesn't remove any further rules.
+type minRules struct{}
+
+var _ planmodifier.Int64 = minRules{}
+
+func (m minRules) Description(ctx context.Context) string {
+	return m.MarkdownDescription(ctx)
+}
+
+func (m minRules) MarkdownDescription(ctx context.Context) string {
+	return "Fails the plan if it reduces the number of rules below min_rules."
+}
+
+func (m minRules) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planned, known, diags := ruleCount(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if !known || resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior, _, diags := ruleCount(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	minimum := req.ConfigValue.ValueInt64()
+	if planned >= minimum || planned >= prior {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Too few traffic filter rules",
+		fmt.Sprintf("The plan reduces the number of rules of the traffic filter from %d to %d, below the minimum of %d set by min_rules. "+
+			"Lower min_rules if the rules are meant to be removed.", prior, planned, minimum),
+	)
+}
+
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target any) diag.Diagnostics
+}
+
+// ruleCount returns the number of rules, however they are defined. The count
+// isn't known if the sources or rule blocks aren't known yet.
+func ruleCount(ctx context.Context, values attributeGetter) (int64, bool, diag.Diagnostics) {
+	var sources, rules types.Set
+	diags := values.GetAttribute(ctx, path.Root("sources"), &sources)
+	diags.Append(values.GetAttribute(ctx, path.Root("rule"), &rules)...)
+	if diags.HasError() {
+		return 0, false, diags
+	}
+
+	if !sources.IsNull() {
+		return int64(len(sources.Elements())), !sources.IsUnknown(), diags
+	}
+	return int64(len(rules.Elements())), !rules.IsUnknown(), diags
+}
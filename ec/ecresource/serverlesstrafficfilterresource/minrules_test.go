@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func ruleBlocks(sources ...string) []TrafficFilterRuleModel {
+	rules := make([]TrafficFilterRuleModel, 0, len(sources))
+	for _, source := range sources {
+		rules = append(rules, TrafficFilterRuleModel{Source: types.StringValue(source), Description: types.StringNull()})
+	}
+	return rules
+}
+
+func TestMinRules(t *testing.T) {
+	withRules := func(minRules types.Int64, sources ...string) *TrafficFilterModel {
+		model := testModel()
+		model.ID = types.StringValue("filter-id")
+		model.MinRules = minRules
+		model.Rules = ruleBlocks(sources...)
+		return &model
+	}
+	withSources := func(minRules types.Int64, sources ...string) *TrafficFilterModel {
+		model := sourcesModel(sources, nil)
+		model.ID = types.StringValue("filter-id")
+		model.MinRules = minRules
+		return &model
+	}
+
+	tests := []struct {
+		name        string
+		state       *TrafficFilterModel
+		plan        *TrafficFilterModel
+		expectError bool
+	}{
+		{
+			name:        "fails if the plan shrinks the rules below the minimum",
+			state:       withRules(types.Int64Value(2), "1.1.1.1", "2.2.2.2", "3.3.3.3"),
+			plan:        withRules(types.Int64Value(2), "1.1.1.1"),
+			expectError: true,
+		},
+		{
+			name:        "fails if the plan shrinks the sources below the minimum",
+			state:       withSources(types.Int64Value(2), "1.1.1.1", "2.2.2.2"),
+			plan:        withSources(types.Int64Value(2), "1.1.1.1"),
+			expectError: true,
+		},
+		{
+			name:  "allows removing rules down to the minimum",
+			state: withRules(types.Int64Value(2), "1.1.1.1", "2.2.2.2", "3.3.3.3"),
+			plan:  withRules(types.Int64Value(2), "1.1.1.1", "2.2.2.2"),
+		},
+		{
+			name:  "allows changing rules already below the minimum without removing any",
+			state: withRules(types.Int64Value(3), "1.1.1.1", "2.2.2.2"),
+			plan:  withRules(types.Int64Value(3), "1.1.1.1", "4.4.4.4"),
+		},
+		{
+			name: "allows creating a traffic filter with fewer rules",
+			plan: withRules(types.Int64Value(2), "1.1.1.1"),
+		},
+		{
+			name:  "allows shrinking without a minimum",
+			state: withRules(types.Int64Null(), "1.1.1.1", "2.2.2.2"),
+			plan:  withRules(types.Int64Null(), "1.1.1.1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			schemaResp := testSchema(t)
+
+			plan := testPlan(t, *tt.plan)
+			state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			if tt.state != nil {
+				state = testState(t, *tt.state)
+			}
+
+			attribute, ok := schemaResp.Schema.Attributes["min_rules"].(schema.Int64Attribute)
+			require.True(t, ok)
+
+			resp := planmodifier.Int64Response{PlanValue: tt.plan.MinRules}
+			for _, modifier := range attribute.PlanModifiers {
+				modifier.PlanModifyInt64(ctx, planmodifier.Int64Request{
+					Path:        path.Root("min_rules"),
+					Config:      tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+					State:       state,
+					Plan:        plan,
+					ConfigValue: tt.plan.MinRules,
+					PlanValue:   tt.plan.MinRules,
+				}, &resp)
+			}
+
+			require.Equal(t, tt.expectError, resp.Diagnostics.HasError(), resp.Diagnostics)
+			if tt.expectError {
+				require.Equal(t, "Too few traffic filter rules", resp.Diagnostics.Errors()[0].Summary())
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// disallowedSourceErrors reports the rules with an IP source which isn't
+// contained in any of the CIDR masks allowed by the provider configuration.
+// Any source is allowed if the provider doesn't restrict them.
+func disallowedSourceErrors(model TrafficFilterModel, rules []TrafficFilterRuleModel, allowed []netip.Prefix) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if allowed == nil {
+		return diags
+	}
+
+	allowedStrings := make([]string, 0, len(allowed))
+	for _, prefix := range allowed {
+		allowedStrings = append(allowedStrings, prefix.String())
+	}
+
+	for _, rule := range rules {
+		source := rule.Source.ValueString()
+		prefix, ok := sourcePrefix(source)
+		if !ok || isAllowedSource(prefix, allowed) {
+			continue
+		}
+		diags.AddAttributeError(
+			rulePath(model, rule),
+			"Traffic filter rule source not allowed",
+			fmt.Sprintf("The rule with the source %s is not allowed by the provider configuration, which only allows sources within: %s",
+				source, strings.Join(allowedStrings, ", ")),
+		)
+	}
+	return diags
+}
+
+// sourcePrefix parses an IP address or CIDR mask source. Other kinds of sources aren't restricted.
+func sourcePrefix(source string) (netip.Prefix, bool) {
+	source = strings.TrimSpace(source)
+	if prefix, err := netip.ParsePrefix(source); err == nil {
+		return prefix.Masked(), true
+	}
+	if addr, err := netip.ParseAddr(source); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+	return netip.Prefix{}, false
+}
+
+func isAllowedSource(source netip.Prefix, allowed []netip.Prefix) bool {
+	for _, prefix := range allowed {
+		if prefix.Bits() <= source.Bits() && prefix.Contains(source.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameMismatchErrors reports a name which doesn't match the pattern required by the provider
+// configuration. Any name is allowed if the provider doesn't restrict them, and unknown names
+// are checked once they're known.
+func nameMismatchErrors(name types.String, pattern *regexp.Regexp) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if pattern == nil || name.IsNull() || name.IsUnknown() {
+		return diags
+	}
+
+	if !pattern.MatchString(name.ValueString()) {
+		diags.AddAttributeError(
+			path.Root("name"),
+			"Traffic filter name not allowed",
+			fmt.Sprintf("The name %q doesn't match the pattern %q required by the provider configuration", name.ValueString(), pattern.String()),
+		)
+	}
+	return diags
+}
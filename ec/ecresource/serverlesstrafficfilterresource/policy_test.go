@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+)
+
+func TestDisallowedSourceErrors(t *testing.T) {
+	allowed := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+
+	tests := []struct {
+		name       string
+		allowed    []netip.Prefix
+		sources    []string
+		disallowed []string
+	}{
+		{
+			name:    "allows everything without a policy",
+			sources: []string{"0.0.0.0/0", "1.1.1.1"},
+		},
+		{
+			name:    "allows addresses and masks within the allowed ranges",
+			allowed: allowed,
+			sources: []string{"10.1.2.3", "10.1.0.0/16", "10.0.0.0/8", "2001:db8::1", " 10.0.0.1/32 "},
+		},
+		{
+			name:       "rejects addresses and masks outside of the allowed ranges",
+			allowed:    allowed,
+			sources:    []string{"10.1.2.3", "192.168.0.1", "0.0.0.0/0", "2001:db9::1"},
+			disallowed: []string{"192.168.0.1", "0.0.0.0/0", "2001:db9::1"},
+		},
+		{
+			name:       "rejects masks only partially within an allowed range",
+			allowed:    allowed,
+			sources:    []string{"10.0.0.0/7"},
+			disallowed: []string{"10.0.0.0/7"},
+		},
+		{
+			name:    "ignores sources which aren't IP addresses",
+			allowed: allowed,
+			sources: []string{"vpce-0123456789abcdef0", "9b4c5d2e-1a2b-4c3d-8e9f-0a1b2c3d4e5f"},
+		},
+		{
+			name:       "rejects every IP source with an empty policy",
+			allowed:    []netip.Prefix{},
+			sources:    []string{"10.1.2.3"},
+			disallowed: []string{"10.1.2.3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := sourcesModel(tt.sources, nil)
+			rules := make([]TrafficFilterRuleModel, 0, len(tt.sources))
+			for _, source := range tt.sources {
+				rules = append(rules, TrafficFilterRuleModel{Source: types.StringValue(source), Description: types.StringNull()})
+			}
+
+			diags := disallowedSourceErrors(model, rules, tt.allowed)
+			require.Len(t, diags.Errors(), len(tt.disallowed))
+			for i, source := range tt.disallowed {
+				require.Equal(t, "Traffic filter rule source not allowed", diags.Errors()[i].Summary())
+				require.Contains(t, diags.Errors()[i].Detail(), "The rule with the source "+source+" ")
+				require.Equal(t, path.Root("sources").AtSetValue(types.StringValue(source)), diags.Errors()[i].(interface{ Path() path.Path }).Path())
+			}
+		})
+	}
+}
+
+func TestCreate_RejectsDisallowedSources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	// The API must not be called.
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	r := &Resource{client: mockClient, allowedSourceCIDRs: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter rule source not allowed", resp.Diagnostics.Errors()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "1.1.1.1")
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "10.0.0.0/8")
+	require.True(t, resp.State.Raw.IsNull())
+}
+
+func TestCreate_AllowsSourcesWithinPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		JSON201: &serverless.TrafficFilterInfo{
+			Id:     "filter-id",
+			Name:   "my-filter",
+			Region: "us-east-1",
+			Type:   "ip",
+			Rules:  []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+	}, nil)
+	r := &Resource{client: mockClient, allowedSourceCIDRs: []netip.Prefix{netip.MustParsePrefix("1.1.0.0/16")}}
+
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+}
+
+func TestNameMismatchErrors(t *testing.T) {
+	pattern := regexp.MustCompile(`^(prod|staging)-[a-z0-9-]+$`)
+
+	tests := []struct {
+		name     string
+		pattern  *regexp.Regexp
+		value    types.String
+		mismatch bool
+	}{
+		{name: "allows any name without a pattern", value: types.StringValue("Anything Goes")},
+		{name: "allows conforming names", pattern: pattern, value: types.StringValue("prod-office-vpn")},
+		{name: "rejects non-conforming names", pattern: pattern, value: types.StringValue("office-vpn"), mismatch: true},
+		{name: "checks unknown names once they're known", pattern: pattern, value: types.StringUnknown()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := nameMismatchErrors(tt.value, tt.pattern)
+			if !tt.mismatch {
+				require.Empty(t, diags)
+				return
+			}
+			require.Len(t, diags, 1)
+			require.Equal(t, "Traffic filter name not allowed", diags[0].Summary())
+			require.Contains(t, diags[0].Detail(), pattern.String())
+		})
+	}
+}
+
+func TestModifyPlan_RejectsNonConformingName(t *testing.T) {
+	model := testModel()
+	model.Name = types.StringValue("office-vpn")
+
+	r := &Resource{namePattern: regexp.MustCompile(`^prod-`)}
+	plan := testPlan(t, model)
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+		Config: testConfig(t, model),
+		Plan:   plan,
+		State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(context.Background()), nil)},
+	}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter name not allowed", resp.Diagnostics.Errors()[0].Summary())
+}
@@ -19,21 +19,44 @@ package serverlesstrafficfilterresource
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/elastic/terraform-provider-ec/ec/internal"
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+	"github.com/elastic/terraform-provider-ec/ec/internal/transport"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &Resource{}
 var _ resource.ResourceWithConfigure = &Resource{}
 var _ resource.ResourceWithImportState = &Resource{}
+var _ resource.ResourceWithModifyPlan = &Resource{}
 
 type Resource struct {
-	client serverless.ClientWithResponsesInterface
+	client             serverless.ClientWithResponsesInterface
+	allowedSourceCIDRs []netip.Prefix
+	namePattern        *regexp.Regexp
+	defaultDescription string
+	mutationWindow     *util.MutationWindow
+	diagnosticsJSONLog bool
+	// filterLists holds listings of traffic filters used to resolve names, which changes invalidate.
+	filterLists *serverlessutil.FilterListCache
+	// sleep waits between polls of deleted traffic filters, timeouts.Sleep if nil.
+	sleep func(time.Duration)
 }
 
 func NewResource() resource.Resource {
@@ -47,58 +70,150 @@ func (r *Resource) Metadata(_ context.Context, req resource.MetadataRequest, res
 func (r *Resource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	clients, diags := internal.ConvertProviderData(req.ProviderData)
 	resp.Diagnostics.Append(diags...)
+	// Leave the resource unconfigured rather than partially configured.
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	r.client = clients.Serverless
+	r.allowedSourceCIDRs = clients.AllowedSourceCIDRs
+	r.namePattern = clients.NamePattern
+	r.defaultDescription = clients.DefaultFilterDesc
+	r.mutationWindow = clients.MutationWindow
+	r.diagnosticsJSONLog = clients.DiagnosticsJSONLog
+	r.filterLists = clients.FilterLists
+}
+
+// logDiagnostics logs the diagnostics of an operation as JSON lines, if enabled
+// by the diagnostics_json_log provider attribute.
+func (r *Resource) logDiagnostics(ctx context.Context, diags diag.Diagnostics, failures *transport.Failures) {
+	if r.diagnosticsJSONLog {
+		transport.LogDiagnosticsJSON(ctx, diags, failures)
+	}
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "create traffic filter")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var model TrafficFilterModel
 	diags := req.Plan.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// Listings used to resolve traffic filter names are outdated once a traffic filter of the region changes.
+	defer r.filterLists.Invalidate(model.Region.ValueString())
+
+	rules, diags := model.ruleModels(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(disallowedSourceErrors(model, rules, r.allowedSourceCIDRs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	createReq := serverless.CreateTrafficFilterRequest{
 		Name:             model.Name.ValueString(),
 		Region:           model.Region.ValueString(),
 		Type:             serverless.TrafficFilterType(model.Type.ValueString()),
-		Description:      model.Description.ValueStringPointer(),
-		IncludeByDefault: model.IncludeByDefault.ValueBoolPointer(),
-	}
-
-	if len(model.Rules) > 0 {
-		rules := make([]serverless.TrafficFilterRule, 0, len(model.Rules))
-		for _, rule := range model.Rules {
-			rules = append(rules, serverless.TrafficFilterRule{
-				Source:      rule.Source.ValueString(),
-				Description: rule.Description.ValueStringPointer(),
-			})
-		}
-		createReq.Rules = &rules
+		Description:      apiDescription(model),
+		IncludeByDefault: optionalBool(model.IncludeByDefault),
+		Rules:            apiRules(rules, model.SkipClientValidation.ValueBool()),
 	}
 
 	createResp, err := r.client.CreateTrafficFilterWithResponse(ctx, createReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create traffic filter", err.Error())
+		resp.Diagnostics.AddError(util.APICreateFailed, util.OperationDetail("create traffic filter", util.RequestErrorDetail(err)))
 		return
 	}
 
-	if createResp.JSON201 == nil {
+	// The filter has been created, but we can't tell its ID. Retrying would
+	// create a duplicate filter, so ask the user to import it instead.
+	if createResp.JSON201 == nil && createResp.StatusCode() == http.StatusCreated {
 		resp.Diagnostics.AddError(
-			"Failed to create traffic filter",
-			fmt.Sprintf("The API request failed with: %d %s\n%s",
-				createResp.StatusCode(),
+			"Traffic filter created with an unreadable response",
+			fmt.Sprintf("The API reported the traffic filter as created (%s), but the response body could not be read:\n%s\n\n"+
+				"Do not retry the apply, as this would create a duplicate traffic filter. "+
+				"Instead, import the created traffic filter into the Terraform state using its ID.",
 				createResp.Status(),
 				string(createResp.Body)),
 		)
 		return
 	}
 
-	model = modelFromResponse(createResp.JSON201)
+	if diags := duplicateSourceErrors(model, rules, createResp.JSON400); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if createResp.JSON201 == nil {
+		resp.Diagnostics.AddError(
+			util.APICreateFailed,
+			util.OperationDetail("create traffic filter", util.APIFailureDetail(createResp.HTTPResponse, createResp.Body)),
+		)
+		return
+	}
+
+	info, body := createResp.JSON201, createResp.Body
+	if isMinimalResponse(info) {
+		info, body, diags = r.readCreated(ctx, info, body)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	plan := model
+	model, diags = modelFromResponse(ctx, info, body, model)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(ignoreServerDefaultDescription(ctx, resp.Private, &model, plan)...)
+	// A new traffic filter is only included in projects created later on.
+	count, _ := reportedAssociationCount(body)
+	model.AssociationCount = types.Int64Value(count)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+	resp.Diagnostics.Append(setLastAppliedRules(ctx, resp.Private, rulesFromResponse(info))...)
+}
+
+// isMinimalResponse tells whether a create response only identifies the
+// traffic filter. A traffic filter always has a name and at least one rule,
+// so a response without them doesn't describe the created traffic filter.
+func isMinimalResponse(info *serverless.TrafficFilterInfo) bool {
+	return info.Id != "" && (info.Name == "" || len(info.Rules) == 0)
+}
+
+// readCreated reads a traffic filter whose create response only contained its
+// ID. If it can't be read, the create response is returned along with the
+// errors, so that the created traffic filter is still stored in the state.
+func (r *Resource) readCreated(ctx context.Context, created *serverless.TrafficFilterInfo, body []byte) (*serverless.TrafficFilterInfo, []byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	readResp, err := r.client.GetTrafficFilterWithResponse(ctx, created.Id)
+	if err != nil {
+		diags.AddError(util.APIReadFailed, util.OperationDetail("read created traffic filter", util.RequestErrorDetail(err)))
+		return created, body, diags
+	}
+	if readResp.JSON200 == nil {
+		diags.AddError(
+			util.APIReadFailed,
+			util.OperationDetail("read created traffic filter", util.APIFailureDetail(readResp.HTTPResponse, readResp.Body)),
+		)
+		return created, body, diags
+	}
+	return readResp.JSON200, readResp.Body, diags
 }
 
 func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
 	var model TrafficFilterModel
 	diags := req.State.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
@@ -108,7 +223,7 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 
 	readResp, err := r.client.GetTrafficFilterWithResponse(ctx, model.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read traffic filter", err.Error())
+		resp.Diagnostics.AddError(util.APIReadFailed, util.OperationDetail("read traffic filter", util.RequestErrorDetail(err)))
 		return
 	}
 
@@ -119,120 +234,367 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 
 	if readResp.JSON200 == nil {
 		resp.Diagnostics.AddError(
-			"Failed to read traffic filter",
-			fmt.Sprintf("The API request failed with: %d %s\n%s",
-				readResp.StatusCode(),
-				readResp.Status(),
-				string(readResp.Body)),
+			util.APIReadFailed,
+			util.OperationDetail("read traffic filter", util.APIFailureDetail(readResp.HTTPResponse, readResp.Body)),
 		)
 		return
 	}
 
-	model = modelFromResponse(readResp.JSON200)
+	// Imported traffic filters only have their ID, their type is read from the API.
+	imported := model.Type.IsNull()
+	rules := rulesFromResponse(readResp.JSON200)
+	prior := model
+	model, diags = modelFromResponse(ctx, readResp.JSON200, readResp.Body, model)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(withoutIgnoredDescription(ctx, req.Private, &model, prior)...)
+	if imported {
+		resp.Diagnostics.Append(typeMismatchWarnings(model.Type.ValueString(), rules)...)
+	}
+	if count, ok := reportedAssociationCount(readResp.Body); ok {
+		model.AssociationCount = types.Int64Value(count)
+	} else if imported {
+		model.AssociationCount, diags = r.associationCount(ctx, model.ID.ValueString(), readResp.Body)
+		resp.Diagnostics.Append(diags...)
+	}
+	resp.Diagnostics.Append(checkRulesDrift(ctx, req.Private, model.ID.ValueString(), rules)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+	resp.Diagnostics.Append(setLastAppliedRules(ctx, resp.Private, rules)...)
 }
 
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "update traffic filter")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var model TrafficFilterModel
 	diags := req.Plan.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	defer r.filterLists.Invalidate(model.Region.ValueString())
+
+	rules, diags := model.ruleModels(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(disallowedSourceErrors(model, rules, r.allowedSourceCIDRs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	patchReq := serverless.PatchTrafficFilterRequest{
 		Name:             model.Name.ValueStringPointer(),
-		Description:      model.Description.ValueStringPointer(),
-		IncludeByDefault: model.IncludeByDefault.ValueBoolPointer(),
+		Description:      apiDescription(model),
+		IncludeByDefault: optionalBool(model.IncludeByDefault),
 	}
 
-	if len(model.Rules) > 0 {
-		rules := make([]serverless.TrafficFilterRule, 0, len(model.Rules))
-		for _, rule := range model.Rules {
-			rules = append(rules, serverless.TrafficFilterRule{
-				Source:      rule.Source.ValueString(),
-				Description: rule.Description.ValueStringPointer(),
-			})
+	// Rules are only sent if they changed, so that e.g. a set being reordered
+	// along with a description change doesn't rewrite them.
+	changed := true
+	if !req.State.Raw.IsNull() {
+		var prior TrafficFilterModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+		priorRules, diags := prior.ruleModels(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		changed = rulesChanged(rules, priorRules)
+
+		// A missing description leaves the current one untouched, so it has to be cleared explicitly.
+		if patchReq.Description == nil && (prior.ManageMarker.ValueBool() || !prior.Description.IsNull()) {
+			empty := ""
+			patchReq.Description = &empty
 		}
-		patchReq.Rules = &rules
+	}
+	if changed {
+		patchReq.Rules = apiRules(rules, model.SkipClientValidation.ValueBool())
 	}
 
 	patchResp, err := r.client.PatchTrafficFilterWithResponse(ctx, model.ID.ValueString(), patchReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to update traffic filter", err.Error())
+		resp.Diagnostics.AddError(util.APIUpdateFailed, util.OperationDetail("update traffic filter", util.RequestErrorDetail(err)))
+		return
+	}
+
+	if diags := duplicateSourceErrors(model, rules, patchResp.JSON400); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
 		return
 	}
 
 	if patchResp.JSON200 == nil {
 		resp.Diagnostics.AddError(
-			"Failed to update traffic filter",
-			fmt.Sprintf("The API request failed with: %d %s\n%s",
-				patchResp.StatusCode(),
-				patchResp.Status(),
-				string(patchResp.Body)),
+			util.APIUpdateFailed,
+			util.OperationDetail("update traffic filter", util.APIFailureDetail(patchResp.HTTPResponse, patchResp.Body)),
 		)
 		return
 	}
 
-	model = modelFromResponse(patchResp.JSON200)
+	plan := model
+	model, diags = modelFromResponse(ctx, patchResp.JSON200, patchResp.Body, model)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(withoutIgnoredDescription(ctx, req.Private, &model, plan)...)
+	model.AssociationCount, diags = r.associationCount(ctx, model.ID.ValueString(), patchResp.Body)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+	resp.Diagnostics.Append(setLastAppliedRules(ctx, resp.Private, rulesFromResponse(patchResp.JSON200))...)
 }
 
 func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, failures := transport.CollectFailures(ctx)
+	defer func() { r.logDiagnostics(ctx, resp.Diagnostics, failures) }()
+	ctx, deprecations := transport.CollectDeprecations(ctx)
+	defer func() { resp.Diagnostics.Append(deprecations.Diagnostics()...) }()
+
+	resp.Diagnostics.Append(util.MutationWindowErrors(r.mutationWindow, time.Now(), "delete traffic filter")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var model TrafficFilterModel
 	diags := req.State.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	defer r.filterLists.Invalidate(model.Region.ValueString())
 
 	deleteResp, err := r.client.DeleteTrafficFilterWithResponse(ctx, model.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to delete traffic filter", err.Error())
+		resp.Diagnostics.AddError(util.APIDeleteFailed, util.OperationDetail("delete traffic filter", util.RequestErrorDetail(err)))
 		return
 	}
 
 	statusCode := deleteResp.StatusCode()
 	if statusCode != http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotFound {
 		resp.Diagnostics.AddError(
-			"Failed to delete traffic filter",
-			fmt.Sprintf("The API request failed with: %d %s\n%s",
-				deleteResp.StatusCode(),
-				deleteResp.Status(),
-				string(deleteResp.Body)),
+			util.APIDeleteFailed,
+			util.OperationDetail("delete traffic filter", util.APIFailureDetail(deleteResp.HTTPResponse, deleteResp.Body)),
 		)
 		return
 	}
+
+	// Asynchronous deletions are waited for even without wait_for_deletion, as the traffic filter still exists.
+	if statusCode != http.StatusNotFound && (model.WaitForDeletion.ValueBool() || isAsyncDeletion(statusCode, deleteResp.Body)) {
+		timeout, diags := timeouts.Delete(model.Timeouts, defaultDeletionTimeout)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(r.waitForDeletion(ctx, model.ID.ValueString(), timeout)...)
+	}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan TrafficFilterModel
+	var rulesKnown bool
+	if !req.Plan.Raw.IsNull() {
+		var diags diag.Diagnostics
+		rulesKnown, diags = planModel(ctx, req.Plan, &plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if rulesKnown {
+			planRuleSources(ctx, plan, resp)
+		}
+		r.planDescription(ctx, req, resp)
+		resp.Diagnostics.Append(nameMismatchErrors(plan.Name, r.namePattern)...)
+	}
+
+	// Nothing to warn about when creating the filter.
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state TrafficFilterModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(r.warnAboutAssociations(ctx, state.ID.ValueString(), "destroyed")...)
+		return
+	}
+
+	reason := replacementReason(plan, state)
+	if reason != "" {
+		resp.Diagnostics.Append(r.warnAboutAssociations(ctx, state.ID.ValueString(), "replaced due to the "+reason)...)
+	}
+
+	// The applied rules count is kept from the state, unless the rules change.
+	if reason != "" || !rulesKnown || planRulesChanged(ctx, plan, state) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("applied_rules_count"), types.Int64Unknown())...)
+	}
+}
+
+// planRulesChanged tells whether the planned rules differ from the ones in the state.
+// Rules which aren't known yet are considered to be changed.
+func planRulesChanged(ctx context.Context, plan, state TrafficFilterModel) bool {
+	if plan.Sources.IsUnknown() || plan.RuleDescriptions.IsUnknown() || plan.RuleDescriptionTemplate.IsUnknown() {
+		return true
+	}
+
+	planned, diags := plan.ruleModels(ctx)
+	if diags.HasError() {
+		return true
+	}
+	prior, diags := state.ruleModels(ctx)
+	if diags.HasError() {
+		return true
+	}
+	return rulesChanged(planned, prior)
+}
+
+// planModel reads the plan into model. The rule blocks are unknown when generated by a dynamic
+// block whose for_each isn't known yet, in which case the model only holds the attributes checked
+// regardless of the rules, and false is returned. The plan is modified again once they're known.
+func planModel(ctx context.Context, plan tfsdk.Plan, model *TrafficFilterModel) (bool, diag.Diagnostics) {
+	var rules types.Set
+	diags := plan.GetAttribute(ctx, path.Root("rule"), &rules)
+	if diags.HasError() {
+		return false, diags
+	}
+	if !rules.IsUnknown() {
+		diags.Append(plan.Get(ctx, model)...)
+		return true, diags
+	}
+
+	diags.Append(plan.GetAttribute(ctx, path.Root("name"), &model.Name)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("region"), &model.Region)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("type"), &model.Type)...)
+	return false, diags
+}
+
+// replacementReason describes the changes of the attributes which require
+// replacing the traffic filter, empty if there are none.
+func replacementReason(plan, state TrafficFilterModel) string {
+	regionChanged := !plan.Region.Equal(state.Region)
+	typeChanged := !plan.Type.Equal(state.Type)
+	switch {
+	case regionChanged && typeChanged:
+		return "region and type change"
+	case regionChanged:
+		return "region change"
+	case typeChanged:
+		return "type change"
+	}
+	return ""
 }
 
 func (r *Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Import IDs are often pasted with stray whitespace.
+	id := strings.TrimSpace(req.ID)
+
+	// Check the ID right away, as the read following the import silently
+	// removes traffic filters which don't exist from the state.
+	readResp, err := r.client.GetTrafficFilterWithResponse(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError(util.APIReadFailed, util.OperationDetail("read traffic filter", util.RequestErrorDetail(err)))
+		return
+	}
+
+	if readResp.StatusCode() == http.StatusNotFound {
+		resp.Diagnostics.AddError(
+			"Traffic filter not found",
+			fmt.Sprintf("Traffic filter %q does not exist and can't be imported. Check that the ID is correct and that the traffic filter belongs to the organization of the configured API key.", id),
+		)
+		return
+	}
+
+	if readResp.JSON200 == nil {
+		resp.Diagnostics.AddError(
+			util.APIReadFailed,
+			util.OperationDetail("read traffic filter", util.APIFailureDetail(readResp.HTTPResponse, readResp.Body)),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
-func modelFromResponse(info *serverless.TrafficFilterInfo) TrafficFilterModel {
+// modelFromResponse converts the API response into a model, representing
+// the rules in the same way as the prior model. The association count is
+// kept from the prior model, it's only refreshed on create, update and import
+// unless the API reports it.
+func modelFromResponse(ctx context.Context, info *serverless.TrafficFilterInfo, body []byte, prior TrafficFilterModel) (TrafficFilterModel, diag.Diagnostics) {
 	model := TrafficFilterModel{}
 	model.ID = stringValue(info.Id)
 	model.Name = stringValue(info.Name)
 	model.Region = stringValue(info.Region)
 	model.Type = stringValue(string(info.Type))
-	model.IncludeByDefault = boolValue(info.IncludeByDefault)
-
-	if info.Description != nil && *info.Description != "" {
-		model.Description = stringValue(*info.Description)
-	}
-
-	if len(info.Rules) > 0 {
-		model.Rules = make([]TrafficFilterRuleModel, 0, len(info.Rules))
-		for _, rule := range info.Rules {
-			ruleModel := TrafficFilterRuleModel{
-				Source: stringValue(rule.Source),
-			}
-			if rule.Description != nil && *rule.Description != "" {
-				ruleModel.Description = stringValue(*rule.Description)
-			}
-			model.Rules = append(model.Rules, ruleModel)
+	model.IncludeByDefault = includeByDefault(ctx, info, body)
+	model.AssociationCount = prior.AssociationCount
+	model.AppliedRulesCount = types.Int64Value(int64(len(info.Rules)))
+	model.ReplaceOnRulesChange = prior.ReplaceOnRulesChange
+	if model.ReplaceOnRulesChange.IsNull() {
+		// Not known when importing, use the default.
+		model.ReplaceOnRulesChange = boolValue(false)
+	}
+	model.MinRules = prior.MinRules
+	model.WaitForDeletion = prior.WaitForDeletion
+	if model.WaitForDeletion.IsNull() {
+		model.WaitForDeletion = boolValue(false)
+	}
+	model.SkipClientValidation = prior.SkipClientValidation
+	if model.SkipClientValidation.IsNull() {
+		model.SkipClientValidation = boolValue(false)
+	}
+	model.Timeouts = prior.Timeouts
+	if model.Timeouts.IsNull() {
+		// Not known when importing.
+		model.Timeouts = types.ObjectNull(timeouts.AttrTypes(timeoutOpts))
+	}
+
+	model.Description, model.ManageMarker = descriptionFromResponse(info.Description, prior)
+
+	diags := model.setRules(ctx, rulesFromResponse(info), prior)
+	return model, diags
+}
+
+// includeByDefault returns whether the traffic filter is included in new projects by default.
+// Depending on the API version, it's reported as include_by_default or as default, so the raw
+// body is checked for both fields. Bodies with neither of them fall back to the decoded info.
+func includeByDefault(ctx context.Context, info *serverless.TrafficFilterInfo, body []byte) types.Bool {
+	var fields struct {
+		IncludeByDefault *bool `json:"include_by_default"`
+		Default          *bool `json:"default"`
+	}
+	if err := json.Unmarshal(body, &fields); err == nil {
+		switch {
+		case fields.IncludeByDefault != nil:
+			tflog.Debug(ctx, "Traffic filter response reports include_by_default", map[string]interface{}{"id": info.Id})
+			return boolValue(*fields.IncludeByDefault)
+		case fields.Default != nil:
+			tflog.Debug(ctx, "Traffic filter response reports default instead of include_by_default", map[string]interface{}{"id": info.Id})
+			return boolValue(*fields.Default)
 		}
 	}
+	return boolValue(info.IncludeByDefault)
+}
+
+func rulesFromResponse(info *serverless.TrafficFilterInfo) []TrafficFilterRuleModel {
+	if len(info.Rules) == 0 {
+		return nil
+	}
 
-	return model
+	rules := make([]TrafficFilterRuleModel, 0, len(info.Rules))
+	for _, rule := range info.Rules {
+		ruleModel := TrafficFilterRuleModel{
+			Source:     stringValue(rule.Source),
+			SourceKind: stringValue(detectSourceKind(rule.Source)),
+		}
+		if rule.Description != nil && *rule.Description != "" {
+			ruleModel.Description = stringValue(*rule.Description)
+		}
+		rules = append(rules, ruleModel)
+	}
+	return rules
 }
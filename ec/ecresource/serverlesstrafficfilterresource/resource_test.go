@@ -0,0 +1,941 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func testSchema(t *testing.T) resource.SchemaResponse {
+	schemaResp := resource.SchemaResponse{}
+	(&Resource{}).Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	return schemaResp
+}
+
+func testPlan(t *testing.T, model TrafficFilterModel) tfsdk.Plan {
+	schemaResp := testSchema(t)
+	return tfsdk.Plan{
+		Schema: schemaResp.Schema,
+		Raw:    util.TfTypesValueFromGoTypeValue(t, model, schemaResp.Schema.Type()),
+	}
+}
+
+func testConfig(t *testing.T, model TrafficFilterModel) tfsdk.Config {
+	schemaResp := testSchema(t)
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    util.TfTypesValueFromGoTypeValue(t, model, schemaResp.Schema.Type()),
+	}
+}
+
+func testState(t *testing.T, model TrafficFilterModel) tfsdk.State {
+	schemaResp := testSchema(t)
+	return tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    util.TfTypesValueFromGoTypeValue(t, model, schemaResp.Schema.Type()),
+	}
+}
+
+// withUnknownRules replaces the rule blocks of a raw value by an unknown value, as
+// planned for a dynamic block whose for_each isn't known yet.
+func withUnknownRules(t *testing.T, raw tftypes.Value) tftypes.Value {
+	value, err := tftypes.Transform(raw, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if p.Equal(tftypes.NewAttributePath().WithAttributeName("rule")) {
+			return tftypes.NewValue(v.Type(), tftypes.UnknownValue), nil
+		}
+		return v, nil
+	})
+	require.NoError(t, err)
+	return value
+}
+
+// initPrivateState initialises the Private field of a framework request or
+// response, since its type can't be constructed outside the framework.
+func initPrivateState(t *testing.T, target any) {
+	field := reflect.ValueOf(target).Elem().FieldByName("Private")
+	require.True(t, field.IsValid())
+	field.Set(reflect.New(field.Type().Elem()))
+}
+
+func testModel() TrafficFilterModel {
+	return TrafficFilterModel{
+		ID:               types.StringUnknown(),
+		Name:             types.StringValue("my-filter"),
+		Type:             types.StringValue("ip"),
+		Region:           types.StringValue("us-east-1"),
+		Description:      types.StringNull(),
+		IncludeByDefault: types.BoolValue(false),
+		Sources:          types.SetNull(types.StringType),
+		RuleDescriptions: types.MapNull(types.StringType),
+		RuleSources:      types.SetNull(types.StringType),
+		Timeouts:         types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+		Rules: []TrafficFilterRuleModel{
+			{Source: types.StringValue("1.1.1.1"), Description: types.StringNull()},
+		},
+	}
+}
+
+func TestCreate_CreatedWithUnreadableBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		Body:         []byte("not json"),
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated, Status: "201 Created"},
+	}, nil)
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter created with an unreadable response", resp.Diagnostics[0].Summary())
+	require.Contains(t, resp.Diagnostics[0].Detail(), "import")
+	require.True(t, resp.State.Raw.IsNull())
+}
+
+func TestCreate_MutationWindow(t *testing.T) {
+	window := func(from, to time.Duration) *util.MutationWindow {
+		now := time.Now().UTC()
+		w, err := util.ParseMutationWindow(now.Add(from).Format("15:04") + "-" + now.Add(to).Format("15:04"))
+		require.NoError(t, err)
+		return w
+	}
+
+	t.Run("allowed within the window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+		mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+		}, nil)
+
+		r := &Resource{client: mockClient, mutationWindow: window(-time.Hour, time.Hour)}
+		plan := testPlan(t, testModel())
+		resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+		r.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+		require.Equal(t, util.APICreateFailed, resp.Diagnostics[0].Summary())
+	})
+
+	t.Run("blocked outside of the window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+
+		r := &Resource{client: mockClient, mutationWindow: window(time.Hour, 2*time.Hour)}
+		plan := testPlan(t, testModel())
+		resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+		r.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		require.Equal(t, "Outside of the allowed mutation window", resp.Diagnostics[0].Summary())
+		require.True(t, resp.State.Raw.IsNull())
+	})
+}
+
+func TestCreate_Failed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		Body:         []byte(`{"errors":[]}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+	}, nil)
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, util.APICreateFailed, resp.Diagnostics[0].Summary())
+}
+
+func TestCreate_LogsDiagnosticsAsJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		Body:         []byte(`{"errors":[]}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+	}, nil)
+
+	r := &Resource{client: mockClient, diagnosticsJSONLog: true}
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.True(t, resp.Diagnostics.HasError())
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+
+	var lines []map[string]string
+	for _, entry := range entries {
+		var line map[string]string
+		if json.Unmarshal([]byte(entry["@message"].(string)), &line) == nil {
+			lines = append(lines, line)
+		}
+	}
+	require.Len(t, lines, 1)
+	require.Equal(t, util.APICreateFailed, lines[0]["summary"])
+	require.Equal(t, "Error", lines[0]["severity"])
+	require.Equal(t, resp.Diagnostics[0].Detail(), lines[0]["detail"])
+}
+
+func TestCreate_StoresLastAppliedRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	description := "rule"
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		JSON201: &serverless.TrafficFilterInfo{
+			Id:     "filter-id",
+			Name:   "my-filter",
+			Region: "us-east-1",
+			Type:   "ip",
+			Rules:  []serverless.TrafficFilterRule{{Source: "1.1.1.1", Description: &description}},
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+	}, nil)
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	snapshot, diags := resp.Private.GetKey(ctx, lastAppliedRulesKey)
+	require.False(t, diags.HasError())
+	require.JSONEq(t, `[{"source":"1.1.1.1","description":"rule"}]`, string(snapshot))
+}
+
+func TestCreate_ReadsFilterAfterMinimalResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+		JSON201:      &serverless.TrafficFilterInfo{Id: "filter-id"},
+		Body:         []byte(`{"id":"filter-id"}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+	}, nil)
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+		JSON200: &serverless.TrafficFilterInfo{
+			Id:     "filter-id",
+			Name:   "my-filter",
+			Region: "us-east-1",
+			Type:   "ip",
+			Rules:  []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, testModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, "my-filter", state.Name.ValueString())
+	require.Len(t, state.Rules, 1)
+	require.Equal(t, "1.1.1.1", state.Rules[0].Source.ValueString())
+
+	snapshot, diags := resp.Private.GetKey(ctx, lastAppliedRulesKey)
+	require.False(t, diags.HasError())
+	require.JSONEq(t, `[{"source":"1.1.1.1"}]`, string(snapshot))
+}
+
+func TestRead_ReportsExternalRuleAddition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	model := testModel()
+	model.ID = types.StringValue("filter-id")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+		JSON200: &serverless.TrafficFilterInfo{
+			Id:     "filter-id",
+			Name:   "my-filter",
+			Region: "us-east-1",
+			Type:   "ip",
+			Rules: []serverless.TrafficFilterRule{
+				{Source: "1.1.1.1"},
+				{Source: "2.2.2.2"},
+			},
+		},
+		Body:         []byte(`{"association_count":0}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	r := &Resource{client: mockClient}
+	state := testState(t, model)
+	req := resource.ReadRequest{State: state}
+	initPrivateState(t, &req)
+	require.False(t, setLastAppliedRules(ctx, req.Private, model.Rules).HasError())
+
+	resp := resource.ReadResponse{State: state}
+	initPrivateState(t, &resp)
+	r.Read(ctx, req, &resp)
+
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	warning := resp.Diagnostics.Warnings()[0]
+	require.Equal(t, "Traffic filter rules changed outside of Terraform", warning.Summary())
+	require.Contains(t, warning.Detail(), "Added rules:\n  - 2.2.2.2")
+	require.NotContains(t, warning.Detail(), "Removed rules")
+
+	var newState TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &newState).HasError())
+	require.Len(t, newState.Rules, 2)
+
+	snapshot, diags := resp.Private.GetKey(ctx, lastAppliedRulesKey)
+	require.False(t, diags.HasError())
+	require.JSONEq(t, `[{"source":"1.1.1.1"},{"source":"2.2.2.2"}]`, string(snapshot))
+}
+
+func TestRead_NoWarningWithoutSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	model := testModel()
+	model.ID = types.StringValue("filter-id")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+		JSON200: &serverless.TrafficFilterInfo{
+			Id:    "filter-id",
+			Type:  "ip",
+			Rules: []serverless.TrafficFilterRule{{Source: "2.2.2.2"}},
+		},
+		Body:         []byte(`{"association_count":0}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	r := &Resource{client: mockClient}
+	state := testState(t, model)
+	req := resource.ReadRequest{State: state}
+	initPrivateState(t, &req)
+	resp := resource.ReadResponse{State: state}
+	initPrivateState(t, &resp)
+	r.Read(ctx, req, &resp)
+
+	require.Empty(t, resp.Diagnostics)
+}
+
+// expectAssociatedProjects expects a scan of all projects, three of which are associated with the
+// traffic filter. The Elasticsearch projects span two pages, one associated project being on each.
+func expectAssociatedProjects(mockClient *mocks.MockClientWithResponsesInterface, filterID string) {
+	filters := serverless.TrafficFilters{{Id: filterID}}
+	nextPage := "page-2"
+	mockClient.EXPECT().ListElasticsearchProjectsWithResponse(gomock.Any(), &serverless.ListElasticsearchProjectsParams{}).Return(&serverless.ListElasticsearchProjectsResponse{
+		JSON200: &serverless.ElasticsearchProjectList{
+			Items:    []serverless.ElasticsearchProject{{Id: "es-1", Name: "search", TrafficFilters: &filters}, {Id: "es-2", Name: "other"}},
+			NextPage: &nextPage,
+		},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().ListElasticsearchProjectsWithResponse(gomock.Any(), &serverless.ListElasticsearchProjectsParams{NextPage: &nextPage}).Return(&serverless.ListElasticsearchProjectsResponse{
+		JSON200:      &serverless.ElasticsearchProjectList{Items: []serverless.ElasticsearchProject{{Id: "es-3", Name: "logs", TrafficFilters: &filters}}},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().ListObservabilityProjectsWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.ListObservabilityProjectsResponse{
+		JSON200:      &serverless.ObservabilityProjectList{},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	mockClient.EXPECT().ListSecurityProjectsWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.ListSecurityProjectsResponse{
+		JSON200:      &serverless.SecurityProjectList{Items: []serverless.SecurityProject{{Id: "sec-1", Name: "siem", TrafficFilters: &filters}}},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+}
+
+func TestModifyPlan_WarnsAboutAssociationsOnReplace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	state := testModel()
+	state.ID = types.StringValue("filter-id")
+	plan := state
+	plan.Region = types.StringValue("eu-west-1")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectAssociatedProjects(mockClient, "filter-id")
+
+	r := &Resource{client: mockClient}
+	req := resource.ModifyPlanRequest{State: testState(t, state), Plan: testPlan(t, plan), Config: testConfig(t, plan)}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, &resp)
+
+	require.False(t, resp.Diagnostics.HasError())
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	detail := resp.Diagnostics.Warnings()[0].Detail()
+	require.Contains(t, detail, "replaced due to the region change")
+	require.Contains(t, detail, "search (elasticsearch project es-1)")
+	require.Contains(t, detail, "logs (elasticsearch project es-3)")
+	require.Contains(t, detail, "siem (security project sec-1)")
+	require.NotContains(t, detail, "es-2")
+}
+
+func TestModifyPlan_WarnsAboutAssociationsOnTypeChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	state := testModel()
+	state.ID = types.StringValue("filter-id")
+	plan := state
+	plan.Type = types.StringValue("vpce")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectAssociatedProjects(mockClient, "filter-id")
+
+	r := &Resource{client: mockClient}
+	req := resource.ModifyPlanRequest{State: testState(t, state), Plan: testPlan(t, plan), Config: testConfig(t, plan)}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, &resp)
+
+	require.False(t, resp.Diagnostics.HasError())
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	detail := resp.Diagnostics.Warnings()[0].Detail()
+	require.Contains(t, detail, "replaced due to the type change")
+	require.Contains(t, detail, "search (elasticsearch project es-1)")
+	require.Contains(t, detail, "siem (security project sec-1)")
+}
+
+func TestModifyPlan_UnknownRuleBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	state := testModel()
+	state.ID = types.StringValue("filter-id")
+	plan := state
+	plan.Region = types.StringValue("eu-west-1")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectAssociatedProjects(mockClient, "filter-id")
+
+	req := resource.ModifyPlanRequest{State: testState(t, state), Plan: testPlan(t, plan), Config: testConfig(t, plan)}
+	req.Plan.Raw = withUnknownRules(t, req.Plan.Raw)
+	req.Config.Raw = withUnknownRules(t, req.Config.Raw)
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	(&Resource{client: mockClient}).ModifyPlan(ctx, req, &resp)
+
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	require.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "replaced due to the region change")
+}
+
+func TestReplacementReason(t *testing.T) {
+	state := testModel()
+
+	regionChanged := state
+	regionChanged.Region = types.StringValue("eu-west-1")
+	typeChanged := state
+	typeChanged.Type = types.StringValue("vpce")
+	bothChanged := typeChanged
+	bothChanged.Region = types.StringValue("eu-west-1")
+
+	require.Equal(t, "", replacementReason(state, state))
+	require.Equal(t, "region change", replacementReason(regionChanged, state))
+	require.Equal(t, "type change", replacementReason(typeChanged, state))
+	require.Equal(t, "region and type change", replacementReason(bothChanged, state))
+}
+
+func TestModifyPlan_WarnsAboutAssociationsOnDestroy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	state := testModel()
+	state.ID = types.StringValue("filter-id")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	expectAssociatedProjects(mockClient, "filter-id")
+
+	r := &Resource{client: mockClient}
+	schema := testSchema(t).Schema
+	req := resource.ModifyPlanRequest{
+		State: testState(t, state),
+		Plan:  tfsdk.Plan{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)},
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, &resp)
+
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	require.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "will be destroyed")
+}
+
+func TestModifyPlan_GenericWarningIfAPIUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	state := testModel()
+	state.ID = types.StringValue("filter-id")
+	plan := state
+	plan.Region = types.StringValue("eu-west-1")
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().ListElasticsearchProjectsWithResponse(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	r := &Resource{client: mockClient}
+	req := resource.ModifyPlanRequest{State: testState(t, state), Plan: testPlan(t, plan), Config: testConfig(t, plan)}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, &resp)
+
+	require.False(t, resp.Diagnostics.HasError())
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	require.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "could not be determined: connection refused")
+}
+
+func TestModifyPlan_NoWarningOnInPlaceUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	state := testModel()
+	state.ID = types.StringValue("filter-id")
+	plan := state
+	plan.Name = types.StringValue("renamed")
+
+	r := &Resource{}
+	req := resource.ModifyPlanRequest{State: testState(t, state), Plan: testPlan(t, plan), Config: testConfig(t, plan)}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, &resp)
+
+	require.Empty(t, resp.Diagnostics)
+}
+
+func TestModifyPlan_AppliedRulesCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []TrafficFilterRuleModel
+		expected types.Int64
+	}{
+		{
+			name:     "keeps the count of unchanged rules",
+			rules:    []TrafficFilterRuleModel{{Source: types.StringValue("1.1.1.1"), Description: types.StringNull()}},
+			expected: types.Int64Value(1),
+		},
+		{
+			name: "clears the count of changed rules",
+			rules: []TrafficFilterRuleModel{
+				{Source: types.StringValue("1.1.1.1"), Description: types.StringNull()},
+				{Source: types.StringValue("2.2.2.2"), Description: types.StringNull()},
+			},
+			expected: types.Int64Unknown(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			state := testModel()
+			state.ID = types.StringValue("filter-id")
+			state.AppliedRulesCount = types.Int64Value(1)
+			plan := state
+			plan.Rules = tt.rules
+
+			req := resource.ModifyPlanRequest{State: testState(t, state), Plan: testPlan(t, plan), Config: testConfig(t, plan)}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			(&Resource{}).ModifyPlan(ctx, req, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var count types.Int64
+			require.False(t, resp.Plan.GetAttribute(ctx, path.Root("applied_rules_count"), &count).HasError())
+			require.Equal(t, tt.expected, count)
+		})
+	}
+}
+
+func importState(t *testing.T, r *Resource, id string) resource.ImportStateResponse {
+	ctx := context.Background()
+	schemaResp := testSchema(t)
+
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+	return resp
+}
+
+func TestImportState_TrimsWhitespace(t *testing.T) {
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(gomock.NewController(t))
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+		JSON200:      &serverless.TrafficFilterInfo{Id: "filter-id"},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+
+	resp := importState(t, &Resource{client: mockClient}, " filter-id\n")
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var id types.String
+	require.False(t, resp.State.GetAttribute(ctx, path.Root("id"), &id).HasError())
+	require.Equal(t, "filter-id", id.ValueString())
+}
+
+func TestImportState_FailsForUnknownID(t *testing.T) {
+	mockClient := mocks.NewMockClientWithResponsesInterface(gomock.NewController(t))
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "missing-id").Return(&serverless.GetTrafficFilterResponse{
+		Body:         []byte(`{"errors":[{"message":"not found"}]}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+	}, nil)
+
+	resp := importState(t, &Resource{client: mockClient}, "missing-id")
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Traffic filter not found", resp.Diagnostics.Errors()[0].Summary())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), `Traffic filter "missing-id" does not exist and can't be imported`)
+	require.True(t, resp.State.Raw.IsNull())
+}
+
+func emptyDescriptionsModel() TrafficFilterModel {
+	model := testModel()
+	model.ID = types.StringValue("filter-id")
+	model.Description = types.StringValue("")
+	model.Rules = []TrafficFilterRuleModel{
+		{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("")},
+		{Source: types.StringValue("2.2.2.2"), Description: types.StringNull()},
+	}
+	return model
+}
+
+func TestCreate_OmitsEmptyDescriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+			require.Nil(t, body.Description)
+			require.Equal(t, &[]serverless.TrafficFilterRule{{Source: "1.1.1.1"}, {Source: "2.2.2.2"}}, body.Rules)
+			return &serverless.CreateTrafficFilterResponse{
+				HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+			}, nil
+		})
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, emptyDescriptionsModel())
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+	require.Equal(t, util.APICreateFailed, resp.Diagnostics[0].Summary())
+}
+
+func TestCreate_OmitsUnsetIncludeByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+			require.Nil(t, body.IncludeByDefault)
+			return &serverless.CreateTrafficFilterResponse{
+				JSON201: &serverless.TrafficFilterInfo{
+					Id:               "filter-id",
+					Name:             "my-filter",
+					Region:           "us-east-1",
+					Type:             "ip",
+					IncludeByDefault: true,
+					Rules:            []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+				},
+				HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+			}, nil
+		})
+
+	model := testModel()
+	model.IncludeByDefault = types.BoolUnknown()
+
+	plan := testPlan(t, model)
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, types.BoolValue(true), state.IncludeByDefault)
+}
+
+func TestUpdate_OmitsEmptyDescriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().PatchTrafficFilterWithResponse(gomock.Any(), "filter-id", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, body serverless.PatchTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.PatchTrafficFilterResponse, error) {
+			require.Nil(t, body.Description)
+			require.Equal(t, &[]serverless.TrafficFilterRule{{Source: "1.1.1.1"}, {Source: "2.2.2.2"}}, body.Rules)
+			return &serverless.PatchTrafficFilterResponse{
+				HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+			}, nil
+		})
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, emptyDescriptionsModel())
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan}, &resp)
+	require.Equal(t, util.APIUpdateFailed, resp.Diagnostics[0].Summary())
+}
+
+func TestUpdate_SendsRulesOnlyIfChanged(t *testing.T) {
+	stateModel := testModel()
+	stateModel.ID = types.StringValue("filter-id")
+	stateModel.Rules = []TrafficFilterRuleModel{
+		{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("office")},
+		{Source: types.StringValue("10.0.0.0/8"), Description: types.StringNull()},
+	}
+
+	tests := []struct {
+		name          string
+		rules         []TrafficFilterRuleModel
+		expectedRules *[]serverless.TrafficFilterRule
+	}{
+		{
+			name: "reordered but equal rules",
+			rules: []TrafficFilterRuleModel{
+				{Source: types.StringValue("10.0.0.0/8"), Description: types.StringNull()},
+				{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("office")},
+			},
+		},
+		{
+			name: "rules only differing in their spelling",
+			rules: []TrafficFilterRuleModel{
+				{Source: types.StringValue("10.0.0.0/8"), Description: types.StringValue("")},
+				{Source: types.StringValue(" 1.1.1.1"), Description: types.StringValue("office")},
+			},
+		},
+		{
+			name: "changed rule description",
+			rules: []TrafficFilterRuleModel{
+				{Source: types.StringValue("10.0.0.0/8"), Description: types.StringNull()},
+				{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("home")},
+			},
+			expectedRules: &[]serverless.TrafficFilterRule{
+				{Source: "10.0.0.0/8"},
+				{Source: "1.1.1.1", Description: ec.String("home")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			planModel := stateModel
+			planModel.Description = types.StringValue("updated")
+			planModel.Rules = tt.rules
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().PatchTrafficFilterWithResponse(gomock.Any(), "filter-id", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, body serverless.PatchTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.PatchTrafficFilterResponse, error) {
+					require.Equal(t, ec.String("updated"), body.Description)
+					require.Equal(t, tt.expectedRules, body.Rules)
+					return &serverless.PatchTrafficFilterResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+					}, nil
+				})
+
+			r := &Resource{client: mockClient}
+			plan := testPlan(t, planModel)
+			resp := resource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema}}
+			r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: testState(t, stateModel)}, &resp)
+			require.Equal(t, util.APIUpdateFailed, resp.Diagnostics[0].Summary())
+		})
+	}
+}
+
+func TestRead_AssociationCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		imported bool
+		scan     bool
+		expected int64
+	}{
+		{
+			name:     "uses the count reported by the API",
+			body:     `{"id":"filter-id","association_count":7}`,
+			expected: 7,
+		},
+		{
+			name:     "keeps the prior count without scanning projects",
+			body:     `{"id":"filter-id"}`,
+			expected: 2,
+		},
+		{
+			name:     "falls back to scanning all pages of projects when imported",
+			body:     `{"id":"filter-id"}`,
+			imported: true,
+			scan:     true,
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			model := testModel()
+			model.ID = types.StringValue("filter-id")
+			model.AssociationCount = types.Int64Value(2)
+			if tt.imported {
+				model.Type = types.StringNull()
+				model.AssociationCount = types.Int64Null()
+			}
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+				JSON200: &serverless.TrafficFilterInfo{
+					Id:    "filter-id",
+					Type:  "ip",
+					Rules: []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+				},
+				Body:         []byte(tt.body),
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+			if tt.scan {
+				expectAssociatedProjects(mockClient, "filter-id")
+			}
+
+			r := &Resource{client: mockClient}
+			state := testState(t, model)
+			req := resource.ReadRequest{State: state}
+			initPrivateState(t, &req)
+			resp := resource.ReadResponse{State: state}
+			initPrivateState(t, &resp)
+			r.Read(ctx, req, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var newState TrafficFilterModel
+			require.False(t, resp.State.Get(ctx, &newState).HasError())
+			require.Equal(t, types.Int64Value(tt.expected), newState.AssociationCount)
+		})
+	}
+}
+
+func TestUpdate_CountsAssociations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	model := testModel()
+	model.ID = types.StringValue("filter-id")
+	model.AssociationCount = types.Int64Value(2)
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().PatchTrafficFilterWithResponse(gomock.Any(), "filter-id", gomock.Any()).Return(&serverless.PatchTrafficFilterResponse{
+		JSON200: &serverless.TrafficFilterInfo{
+			Id:    "filter-id",
+			Type:  "ip",
+			Rules: []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+		},
+		Body:         []byte(`{"id":"filter-id"}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil)
+	expectAssociatedProjects(mockClient, "filter-id")
+
+	plan := testPlan(t, model)
+	req := resource.UpdateRequest{Plan: plan, State: testState(t, model)}
+	initPrivateState(t, &req)
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	(&Resource{client: mockClient}).Update(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var newState TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &newState).HasError())
+	require.Equal(t, types.Int64Value(3), newState.AssociationCount)
+}
+
+func TestModelFromResponse_AppliedRulesCount(t *testing.T) {
+	ctx := context.Background()
+	// A rule was added outside of Terraform, the prior model only has one.
+	info := &serverless.TrafficFilterInfo{Id: "filter-id", Type: "ip", Rules: []serverless.TrafficFilterRule{
+		{Source: "1.1.1.1"},
+		{Source: "2.2.2.2"},
+	}}
+
+	prior := testModel()
+	require.Len(t, prior.Rules, 1)
+	model, diags := modelFromResponse(ctx, info, []byte(`{"id":"filter-id"}`), prior)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, types.Int64Value(2), model.AppliedRulesCount)
+}
+
+func TestModelFromResponse_IncludeByDefaultShapes(t *testing.T) {
+	ctx := context.Background()
+	info := &serverless.TrafficFilterInfo{Id: "filter-id", Type: "ip", Rules: []serverless.TrafficFilterRule{{Source: "1.1.1.1"}}}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{name: "include_by_default", body: `{"id":"filter-id","include_by_default":true}`, expected: true},
+		{name: "default", body: `{"id":"filter-id","default":true}`, expected: true},
+		{name: "include_by_default takes precedence", body: `{"id":"filter-id","include_by_default":false,"default":true}`, expected: false},
+		{name: "neither field", body: `{"id":"filter-id"}`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, diags := modelFromResponse(ctx, info, []byte(tt.body), testModel())
+			require.False(t, diags.HasError(), diags)
+			require.Equal(t, types.BoolValue(tt.expected), model.IncludeByDefault)
+		})
+	}
+}
+
+func TestConfigure_UnexpectedProviderData(t *testing.T) {
+	r := &Resource{}
+	resp := resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: "not provider clients"}, &resp)
+
+	require.True(t, resp.Diagnostics.HasError())
+	require.Equal(t, "Unexpected Provider Data", resp.Diagnostics.Errors()[0].Summary())
+	require.Nil(t, r.client)
+	require.Nil(t, r.filterLists)
+}
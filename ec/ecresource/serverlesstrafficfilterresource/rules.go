@@ -0,0 +1,420 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+)
+
+var _ resource.ResourceWithValidateConfig = &Resource{}
+
+// ValidateConfig ensures the rules are defined either by rule blocks or by
+// the sources, rule_descriptions and rule_description_template attributes.
+func (r *Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// The rule blocks are unknown when generated by a dynamic block whose for_each isn't known yet,
+	// they're validated again once known.
+	var rules types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rule"), &rules)...)
+	if resp.Diagnostics.HasError() || rules.IsUnknown() {
+		return
+	}
+
+	var model TrafficFilterModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	usesSources := !model.Sources.IsNull() || !model.RuleDescriptions.IsNull() || !model.RuleDescriptionTemplate.IsNull()
+	if len(model.Rules) > 0 && usesSources {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sources"),
+			"Conflicting traffic filter rules",
+			"The rules of a traffic filter can either be defined by rule blocks or by the sources, rule_descriptions and rule_description_template attributes, but not both.",
+		)
+		return
+	}
+
+	if _, err := parseRuleDescriptionTemplate(model.RuleDescriptionTemplate); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("rule_description_template"),
+			"Invalid rule description template",
+			err.Error(),
+		)
+	}
+
+	skipValidation := model.SkipClientValidation.ValueBool()
+	if skipValidation {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("skip_client_validation"),
+			"Traffic filter rule source validation skipped",
+			"The rule sources aren't checked by the provider, invalid sources are only reported by the API when the traffic filter is created or updated.",
+		)
+	} else {
+		for _, rule := range model.Rules {
+			checkSource(model.Type, rule.Source, path.Root("rule"), &resp.Diagnostics)
+		}
+	}
+
+	if model.Sources.IsUnknown() || model.RuleDescriptions.IsUnknown() {
+		return
+	}
+
+	if len(model.Rules) == 0 && len(model.Sources.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sources"),
+			"Missing traffic filter rules",
+			"A traffic filter needs at least one rule, defined either by a rule block or by the sources attribute.",
+		)
+		return
+	}
+
+	var sources []types.String
+	resp.Diagnostics.Append(model.Sources.ElementsAs(ctx, &sources, false)...)
+	var descriptions map[string]types.String
+	resp.Diagnostics.Append(model.RuleDescriptions.ElementsAs(ctx, &descriptions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Sources which aren't known yet may match any of the descriptions.
+	known := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		if source.IsUnknown() {
+			return
+		}
+		known[source.ValueString()] = true
+		if !skipValidation {
+			checkSource(model.Type, source, path.Root("sources"), &resp.Diagnostics)
+		}
+	}
+
+	for _, source := range sortedKeys(descriptions) {
+		if !known[source] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rule_descriptions").AtMapKey(source),
+				"Unknown traffic filter rule source",
+				fmt.Sprintf("The rule_descriptions attribute contains a description for %s, which is not part of the sources attribute.", source),
+			)
+		}
+	}
+}
+
+// checkSource reports rule sources which don't fit the type of the traffic filter.
+func checkSource(filterType, source types.String, p path.Path, diags *diag.Diagnostics) {
+	if filterType.IsUnknown() || source.IsUnknown() {
+		return
+	}
+	if msg := sourceError(filterType.ValueString(), source.ValueString()); msg != "" {
+		diags.AddAttributeError(p, "Invalid traffic filter rule source", msg)
+	}
+}
+
+// ruleModels returns the rules of the model, regardless of whether they are
+// defined by rule blocks or by the sources and rule_descriptions attributes.
+// Sources without a rule_descriptions entry are described by rendering the
+// rule_description_template, if any.
+func (m TrafficFilterModel) ruleModels(ctx context.Context) ([]TrafficFilterRuleModel, diag.Diagnostics) {
+	if m.Sources.IsNull() || m.Sources.IsUnknown() {
+		return m.Rules, nil
+	}
+
+	var diags diag.Diagnostics
+	var sources []string
+	diags.Append(m.Sources.ElementsAs(ctx, &sources, false)...)
+	descriptions := map[string]string{}
+	if !m.RuleDescriptions.IsNull() && !m.RuleDescriptions.IsUnknown() {
+		diags.Append(m.RuleDescriptions.ElementsAs(ctx, &descriptions, false)...)
+	}
+	tmpl, err := parseRuleDescriptionTemplate(m.RuleDescriptionTemplate)
+	if err != nil {
+		diags.AddAttributeError(path.Root("rule_description_template"), "Invalid rule description template", err.Error())
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	rules := make([]TrafficFilterRuleModel, 0, len(sources))
+	for _, source := range sources {
+		rule := TrafficFilterRuleModel{
+			Source:      types.StringValue(source),
+			Description: types.StringNull(),
+		}
+		if description, ok := descriptions[source]; ok {
+			rule.Description = types.StringValue(description)
+		} else if description, err := tmpl.render(source); err != nil {
+			diags.AddAttributeError(path.Root("rule_description_template"), "Invalid rule description template", err.Error())
+		} else if description != "" {
+			rule.Description = types.StringValue(description)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, diags
+}
+
+// apiRules converts the rules for the API, normalizing their sources. If
+// client validation is skipped, sources of unknown kinds are sent as is, as
+// their spelling may matter to the API. Whitespace-only descriptions are
+// treated as no description, see withPriorBlankDescriptions.
+func apiRules(rules []TrafficFilterRuleModel, skipValidation bool) *[]serverless.TrafficFilterRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	result := make([]serverless.TrafficFilterRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, serverless.TrafficFilterRule{
+			Source:      apiSource(rule.Source.ValueString(), skipValidation),
+			Description: ruleDescription(rule.Description),
+		})
+	}
+	return &result
+}
+
+func ruleDescription(description types.String) *string {
+	if isBlank(description.ValueString()) {
+		return nil
+	}
+	return description.ValueStringPointer()
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// withPriorBlankDescriptions keeps the whitespace-only descriptions of the
+// prior rules for rules without a description. Such descriptions aren't sent
+// to the API, so they'd otherwise show up as a diff after every apply.
+func withPriorBlankDescriptions(ctx context.Context, rules []TrafficFilterRuleModel, prior TrafficFilterModel) []TrafficFilterRuleModel {
+	blank := map[string]types.String{}
+	for _, rule := range prior.Rules {
+		if !rule.Description.IsUnknown() && !rule.Description.IsNull() && isBlank(rule.Description.ValueString()) {
+			blank[normalizeSource(rule.Source.ValueString())] = rule.Description
+		}
+	}
+	if !prior.RuleDescriptions.IsNull() && !prior.RuleDescriptions.IsUnknown() {
+		var descriptions map[string]types.String
+		// Invalid descriptions have already been reported when applying the rules.
+		_ = prior.RuleDescriptions.ElementsAs(ctx, &descriptions, false)
+		for source, description := range descriptions {
+			if !description.IsUnknown() && !description.IsNull() && isBlank(description.ValueString()) {
+				blank[normalizeSource(source)] = description
+			}
+		}
+	}
+
+	result := make([]TrafficFilterRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		if description, ok := blank[normalizeSource(rule.Source.ValueString())]; ok && rule.Description.IsNull() {
+			rule.Description = description
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+func apiSource(source string, skipValidation bool) string {
+	if skipValidation && detectSourceKind(source) == sourceKindOther {
+		return source
+	}
+	return normalizeSource(source)
+}
+
+// rulesChanged tells whether the planned rules differ from the prior ones once
+// sent to the API, ignoring their order and the spelling of their sources.
+func rulesChanged(planned, prior []TrafficFilterRuleModel) bool {
+	return !slices.Equal(appliedRulesFromModel(normalizedRules(planned)), appliedRulesFromModel(normalizedRules(prior)))
+}
+
+func normalizedRules(rules []TrafficFilterRuleModel) []TrafficFilterRuleModel {
+	result := make([]TrafficFilterRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		rule.Source = stringValue(normalizeSource(rule.Source.ValueString()))
+		result = append(result, rule)
+	}
+	return result
+}
+
+// setRules stores the given rules in the model, using the same representation
+// as the prior model: rule blocks, or the sources and rule_descriptions attributes.
+// Descriptions rendered from the rule_description_template of the prior model
+// aren't added to rule_descriptions, so that they don't show up as a diff.
+func (m *TrafficFilterModel) setRules(ctx context.Context, rules []TrafficFilterRuleModel, prior TrafficFilterModel) diag.Diagnostics {
+	m.Rules = nil
+	m.Sources = types.SetNull(types.StringType)
+	m.RuleDescriptions = types.MapNull(types.StringType)
+	m.RuleDescriptionTemplate = prior.RuleDescriptionTemplate
+	rules = withPriorSpelling(rules, prior)
+	rules = withPriorBlankDescriptions(ctx, rules, prior)
+
+	var diags diag.Diagnostics
+	m.RuleSources, diags = ruleSources(ctx, rules)
+
+	if prior.Sources.IsNull() {
+		m.Rules = rules
+		return diags
+	}
+
+	priorDescriptions := map[string]string{}
+	if !prior.RuleDescriptions.IsNull() && !prior.RuleDescriptions.IsUnknown() {
+		diags.Append(prior.RuleDescriptions.ElementsAs(ctx, &priorDescriptions, false)...)
+	}
+	// An invalid template has already been reported when applying the rules.
+	tmpl, _ := parseRuleDescriptionTemplate(prior.RuleDescriptionTemplate)
+
+	sources := make([]string, 0, len(rules))
+	descriptions := map[string]string{}
+	for _, rule := range rules {
+		source := rule.Source.ValueString()
+		sources = append(sources, source)
+		if rule.Description.IsNull() {
+			continue
+		}
+		if _, ok := priorDescriptions[source]; !ok {
+			if rendered, err := tmpl.render(source); err == nil && rendered == rule.Description.ValueString() {
+				continue
+			}
+		}
+		descriptions[source] = rule.Description.ValueString()
+	}
+
+	var d diag.Diagnostics
+	m.Sources, d = types.SetValueFrom(ctx, types.StringType, sources)
+	diags.Append(d...)
+
+	if len(descriptions) > 0 || !prior.RuleDescriptions.IsNull() {
+		m.RuleDescriptions, d = types.MapValueFrom(ctx, types.StringType, descriptions)
+		diags.Append(d...)
+	}
+	return diags
+}
+
+// ruleSources returns the set of the sources of the given rules. The sources
+// are sorted, so that the set is built the same way on every read.
+func ruleSources(ctx context.Context, rules []TrafficFilterRuleModel) (types.Set, diag.Diagnostics) {
+	sources := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		sources = append(sources, rule.Source.ValueString())
+	}
+	sort.Strings(sources)
+	return types.SetValueFrom(ctx, types.StringType, sources)
+}
+
+// planRuleSources sets rule_sources in the plan from the planned rules, so that
+// it's only shown as changed if the sources change.
+func planRuleSources(ctx context.Context, plan TrafficFilterModel, resp *resource.ModifyPlanResponse) {
+	if plan.Sources.IsUnknown() {
+		return
+	}
+
+	rules, diags := plan.ruleModels(ctx)
+	if diags.HasError() {
+		// Reported when applying the plan.
+		return
+	}
+	for _, rule := range rules {
+		if rule.Source.IsUnknown() {
+			return
+		}
+	}
+
+	sources, diags := ruleSources(ctx, rules)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("rule_sources"), sources)...)
+}
+
+// duplicateSourceErrors maps errors of the API rejecting the rules since two of them share a
+// source to the attribute path of the rule. The client side checks may not catch all of these,
+// as the API may normalize sources differently.
+func duplicateSourceErrors(model TrafficFilterModel, rules []TrafficFilterRuleModel, badRequest *serverless.BadRequest) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if badRequest == nil {
+		return diags
+	}
+
+	for _, apiErr := range badRequest.Errors {
+		if !strings.Contains(strings.ToLower(apiErr.Code+" "+apiErr.Message), "duplicate") {
+			continue
+		}
+
+		p, source := duplicateRulePath(model, rules, apiErr.Message)
+		detail := fmt.Sprintf("The API rejected the traffic filter rules as they contain a duplicate source: %s", apiErr.Message)
+		if source != "" {
+			detail = fmt.Sprintf("The API rejected the traffic filter rules as multiple rules have the source %s, once normalized by the API: %s", source, apiErr.Message)
+		}
+		diags.AddAttributeError(p, "Duplicate traffic filter rule source", detail)
+	}
+	return diags
+}
+
+// duplicateRulePath returns the path of the rule whose source is mentioned in the error message.
+func duplicateRulePath(model TrafficFilterModel, rules []TrafficFilterRuleModel, message string) (path.Path, string) {
+	root := path.Root("rule")
+	if !model.Sources.IsNull() {
+		root = path.Root("sources")
+	}
+
+	message = strings.ToLower(message)
+	for _, rule := range rules {
+		source := rule.Source.ValueString()
+		if !strings.Contains(message, strings.ToLower(source)) && !strings.Contains(message, normalizeSource(source)) {
+			continue
+		}
+
+		return rulePath(model, rule), source
+	}
+	return root, ""
+}
+
+// rulePath returns the path of the configured rule, either within the sources or the rule blocks.
+func rulePath(model TrafficFilterModel, rule TrafficFilterRuleModel) path.Path {
+	if !model.Sources.IsNull() {
+		return path.Root("sources").AtSetValue(rule.Source)
+	}
+	// Rule blocks are identified by their configured values, which have no source_kind.
+	ruleValue, diags := types.ObjectValue(ruleAttrTypes, map[string]attr.Value{
+		"source":      rule.Source,
+		"description": rule.Description,
+		"source_kind": types.StringNull(),
+	})
+	if diags.HasError() {
+		return path.Root("rule")
+	}
+	return path.Root("rule").AtSetValue(ruleValue)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
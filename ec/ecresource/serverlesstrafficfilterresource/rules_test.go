@@ -0,0 +1,485 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func sourcesModel(sources []string, descriptions map[string]string) TrafficFilterModel {
+	model := testModel()
+	model.Rules = nil
+
+	elems := make([]attr.Value, 0, len(sources))
+	for _, source := range sources {
+		elems = append(elems, types.StringValue(source))
+	}
+	model.Sources = types.SetValueMust(types.StringType, elems)
+
+	if descriptions != nil {
+		descs := make(map[string]attr.Value, len(descriptions))
+		for source, description := range descriptions {
+			descs[source] = types.StringValue(description)
+		}
+		model.RuleDescriptions = types.MapValueMust(types.StringType, descs)
+	}
+	return model
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		model         TrafficFilterModel
+		expectedPath  path.Path
+		expectedError string
+	}{
+		{
+			name:  "rule blocks",
+			model: testModel(),
+		},
+		{
+			name:  "sources with descriptions",
+			model: sourcesModel([]string{"1.1.1.1", "2.2.2.0/24"}, map[string]string{"2.2.2.0/24": "office"}),
+		},
+		{
+			name:          "description for a source which isn't in the set",
+			model:         sourcesModel([]string{"1.1.1.1"}, map[string]string{"2.2.2.0/24": "office"}),
+			expectedPath:  path.Root("rule_descriptions").AtMapKey("2.2.2.0/24"),
+			expectedError: "Unknown traffic filter rule source",
+		},
+		{
+			name: "descriptions without sources",
+			model: func() TrafficFilterModel {
+				m := sourcesModel(nil, map[string]string{"1.1.1.1": "vpn"})
+				m.Sources = types.SetNull(types.StringType)
+				return m
+			}(),
+			expectedPath:  path.Root("sources"),
+			expectedError: "Missing traffic filter rules",
+		},
+		{
+			name: "rule blocks and sources",
+			model: func() TrafficFilterModel {
+				m := sourcesModel([]string{"2.2.2.2"}, nil)
+				m.Rules = testModel().Rules
+				return m
+			}(),
+			expectedPath:  path.Root("sources"),
+			expectedError: "Conflicting traffic filter rules",
+		},
+		{
+			name: "no rules at all",
+			model: func() TrafficFilterModel {
+				m := testModel()
+				m.Rules = nil
+				return m
+			}(),
+			expectedPath:  path.Root("sources"),
+			expectedError: "Missing traffic filter rules",
+		},
+		{
+			name: "invalid rule block source",
+			model: func() TrafficFilterModel {
+				m := testModel()
+				m.Rules[0].Source = types.StringValue("1.1.1.300")
+				return m
+			}(),
+			expectedPath:  path.Root("rule"),
+			expectedError: "Invalid traffic filter rule source",
+		},
+		{
+			name: "ip source in a vpce filter",
+			model: func() TrafficFilterModel {
+				m := sourcesModel([]string{"2.2.2.0/24"}, nil)
+				m.Type = types.StringValue("vpce")
+				return m
+			}(),
+			expectedPath:  path.Root("sources"),
+			expectedError: "Invalid traffic filter rule source",
+		},
+		{
+			name: "unparsable rule description template",
+			model: func() TrafficFilterModel {
+				m := sourcesModel([]string{"1.1.1.1"}, nil)
+				m.RuleDescriptionTemplate = types.StringValue("{{.Source")
+				return m
+			}(),
+			expectedPath:  path.Root("rule_description_template"),
+			expectedError: "Invalid rule description template",
+		},
+		{
+			name: "rule description template referencing an unknown field",
+			model: func() TrafficFilterModel {
+				m := sourcesModel([]string{"1.1.1.1"}, nil)
+				m.RuleDescriptionTemplate = types.StringValue("{{.Region}}")
+				return m
+			}(),
+			expectedPath:  path.Root("rule_description_template"),
+			expectedError: "Invalid rule description template",
+		},
+		{
+			name: "rule blocks and rule description template",
+			model: func() TrafficFilterModel {
+				m := testModel()
+				m.RuleDescriptionTemplate = types.StringValue("{{.Source}}")
+				return m
+			}(),
+			expectedPath:  path.Root("sources"),
+			expectedError: "Conflicting traffic filter rules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaResp := testSchema(t)
+			req := resource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    util.TfTypesValueFromGoTypeValue(t, tt.model, schemaResp.Schema.Type()),
+				},
+			}
+			resp := resource.ValidateConfigResponse{}
+			(&Resource{}).ValidateConfig(context.Background(), req, &resp)
+
+			if tt.expectedError == "" {
+				require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+				return
+			}
+			require.True(t, resp.Diagnostics.HasError())
+			require.Equal(t, tt.expectedError, resp.Diagnostics.Errors()[0].Summary())
+			require.Equal(t, tt.expectedPath, resp.Diagnostics.Errors()[0].(interface{ Path() path.Path }).Path())
+		})
+	}
+}
+
+func TestValidateConfig_SkipClientValidation(t *testing.T) {
+	for _, model := range []TrafficFilterModel{testModel(), sourcesModel([]string{"2.2.2.0/24"}, nil)} {
+		model.Type = types.StringValue("vpce")
+		model.SkipClientValidation = types.BoolValue(true)
+
+		schemaResp := testSchema(t)
+		req := resource.ValidateConfigRequest{
+			Config: tfsdk.Config{
+				Schema: schemaResp.Schema,
+				Raw:    util.TfTypesValueFromGoTypeValue(t, model, schemaResp.Schema.Type()),
+			},
+		}
+		resp := resource.ValidateConfigResponse{}
+		(&Resource{}).ValidateConfig(context.Background(), req, &resp)
+
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		require.Len(t, resp.Diagnostics.Warnings(), 1)
+		require.Equal(t, "Traffic filter rule source validation skipped", resp.Diagnostics.Warnings()[0].Summary())
+	}
+}
+
+func TestValidateConfig_UnknownRuleBlocks(t *testing.T) {
+	config := testConfig(t, testModel())
+	config.Raw = withUnknownRules(t, config.Raw)
+
+	resp := resource.ValidateConfigResponse{}
+	(&Resource{}).ValidateConfig(context.Background(), resource.ValidateConfigRequest{Config: config}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+}
+
+func TestCreate_FromSourcesAndDescriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	office := "office"
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+			require.NotNil(t, body.Rules)
+			rules := *body.Rules
+			sort.Slice(rules, func(i, j int) bool { return rules[i].Source < rules[j].Source })
+			require.Equal(t, []serverless.TrafficFilterRule{
+				{Source: "1.1.1.1"},
+				{Source: "2.2.2.0/24", Description: &office},
+			}, rules)
+
+			return &serverless.CreateTrafficFilterResponse{
+				JSON201: &serverless.TrafficFilterInfo{
+					Id:     "filter-id",
+					Name:   "my-filter",
+					Region: "us-east-1",
+					Type:   "ip",
+					Rules:  rules,
+				},
+				HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+			}, nil
+		})
+
+	r := &Resource{client: mockClient}
+	plan := testPlan(t, sourcesModel([]string{"1.1.1.1", "2.2.2.0/24"}, map[string]string{"2.2.2.0/24": "office"}))
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+	initPrivateState(t, &resp)
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Empty(t, state.Rules)
+
+	var sources []string
+	require.False(t, state.Sources.ElementsAs(ctx, &sources, false).HasError())
+	require.ElementsMatch(t, []string{"1.1.1.1", "2.2.2.0/24"}, sources)
+
+	var descriptions map[string]string
+	require.False(t, state.RuleDescriptions.ElementsAs(ctx, &descriptions, false).HasError())
+	require.Equal(t, map[string]string{"2.2.2.0/24": "office"}, descriptions)
+}
+
+func TestCreate_FromSourcesWithDescriptionTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected map[string]string
+	}{
+		{
+			name:     "with placeholder",
+			template: "allow {{.Source}}",
+			expected: map[string]string{"1.1.1.1": "allow 1.1.1.1", "2.2.2.0/24": "office"},
+		},
+		{
+			name:     "without placeholder",
+			template: "managed by terraform",
+			expected: map[string]string{"1.1.1.1": "managed by terraform", "2.2.2.0/24": "office"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+					require.NotNil(t, body.Rules)
+					sent := map[string]string{}
+					for _, rule := range *body.Rules {
+						require.NotNil(t, rule.Description)
+						sent[rule.Source] = *rule.Description
+					}
+					require.Equal(t, tt.expected, sent)
+
+					return &serverless.CreateTrafficFilterResponse{
+						JSON201: &serverless.TrafficFilterInfo{
+							Id:     "filter-id",
+							Name:   "my-filter",
+							Region: "us-east-1",
+							Type:   "ip",
+							Rules:  *body.Rules,
+						},
+						HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+					}, nil
+				})
+
+			model := sourcesModel([]string{"1.1.1.1", "2.2.2.0/24"}, map[string]string{"2.2.2.0/24": "office"})
+			model.RuleDescriptionTemplate = types.StringValue(tt.template)
+
+			r := &Resource{client: mockClient}
+			plan := testPlan(t, model)
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+			initPrivateState(t, &resp)
+			r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var state TrafficFilterModel
+			require.False(t, resp.State.Get(ctx, &state).HasError())
+			require.Equal(t, tt.template, state.RuleDescriptionTemplate.ValueString())
+
+			// Rendered descriptions stay out of rule_descriptions, so the state matches the config.
+			var descriptions map[string]string
+			require.False(t, state.RuleDescriptions.ElementsAs(ctx, &descriptions, false).HasError())
+			require.Equal(t, map[string]string{"2.2.2.0/24": "office"}, descriptions)
+		})
+	}
+}
+
+func TestRuleSources_MatchConfiguredSources(t *testing.T) {
+	blocks := testModel()
+	blocks.Rules = []TrafficFilterRuleModel{
+		{Source: types.StringValue("2.2.2.0/24"), Description: types.StringValue("office")},
+		{Source: types.StringValue("1.1.1.1"), Description: types.StringNull()},
+	}
+
+	tests := []struct {
+		name  string
+		model TrafficFilterModel
+	}{
+		{name: "rule blocks", model: blocks},
+		{name: "sources", model: sourcesModel([]string{"2.2.2.0/24", "1.1.1.1"}, map[string]string{"2.2.2.0/24": "office"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			expected := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("1.1.1.1"), types.StringValue("2.2.2.0/24")})
+
+			// The sources are known when planning, so they aren't shown as changing on apply.
+			plan := testPlan(t, tt.model)
+			planReq := resource.ModifyPlanRequest{
+				Config: testConfig(t, tt.model),
+				State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+				Plan:   plan,
+			}
+			planResp := resource.ModifyPlanResponse{Plan: plan}
+			(&Resource{}).ModifyPlan(ctx, planReq, &planResp)
+			require.False(t, planResp.Diagnostics.HasError(), planResp.Diagnostics)
+
+			var planned TrafficFilterModel
+			require.False(t, planResp.Plan.Get(ctx, &planned).HasError())
+			require.Equal(t, expected, planned.RuleSources)
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, body serverless.CreateTrafficFilterRequest, _ ...serverless.RequestEditorFn) (*serverless.CreateTrafficFilterResponse, error) {
+					return &serverless.CreateTrafficFilterResponse{
+						JSON201: &serverless.TrafficFilterInfo{
+							Id:     "filter-id",
+							Name:   "my-filter",
+							Region: "us-east-1",
+							Type:   "ip",
+							Rules:  *body.Rules,
+						},
+						HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+					}, nil
+				})
+
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+			initPrivateState(t, &resp)
+			(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: planResp.Plan}, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var state TrafficFilterModel
+			require.False(t, resp.State.Get(ctx, &state).HasError())
+			require.Equal(t, expected, state.RuleSources)
+		})
+	}
+}
+
+func TestCreate_MapsDuplicateSourceErrorsToRule(t *testing.T) {
+	blocks := testModel()
+	blocks.Rules = []TrafficFilterRuleModel{
+		{Source: types.StringValue("1.1.1.1"), Description: types.StringNull()},
+		{Source: types.StringValue("2.2.2.2"), Description: types.StringValue("vpn")},
+	}
+	blockPath := path.Root("rule").AtSetValue(types.ObjectValueMust(ruleAttrTypes, map[string]attr.Value{
+		"source":      types.StringValue("2.2.2.2"),
+		"description": types.StringValue("vpn"),
+		"source_kind": types.StringNull(),
+	}))
+
+	tests := []struct {
+		name         string
+		model        TrafficFilterModel
+		expectedPath path.Path
+	}{
+		{
+			name:         "rule blocks",
+			model:        blocks,
+			expectedPath: blockPath,
+		},
+		{
+			name:         "sources",
+			model:        sourcesModel([]string{"1.1.1.1", "2.2.2.2"}, nil),
+			expectedPath: path.Root("sources").AtSetValue(types.StringValue("2.2.2.2")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().CreateTrafficFilterWithResponse(gomock.Any(), gomock.Any()).Return(&serverless.CreateTrafficFilterResponse{
+				JSON400: &serverless.BadRequest{Errors: []serverless.ErrorResponse{
+					{Code: "traffic_filter.duplicate_rule_source", Message: "Duplicate rule source: 2.2.2.2/32"},
+				}},
+				HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest},
+			}, nil)
+
+			plan := testPlan(t, tt.model)
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema}}
+			initPrivateState(t, &resp)
+			(&Resource{client: mockClient}).Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+
+			require.True(t, resp.Diagnostics.HasError())
+			require.Len(t, resp.Diagnostics.Errors(), 1)
+			require.Equal(t, "Duplicate traffic filter rule source", resp.Diagnostics.Errors()[0].Summary())
+			require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "multiple rules have the source 2.2.2.2")
+			require.Equal(t, tt.expectedPath, resp.Diagnostics.Errors()[0].(interface{ Path() path.Path }).Path())
+		})
+	}
+}
+
+func TestWhitespaceOnlyRuleDescriptions(t *testing.T) {
+	ctx := context.Background()
+	info := &serverless.TrafficFilterInfo{
+		Id:     "filter-id",
+		Name:   "my-filter",
+		Region: "us-east-1",
+		Type:   "ip",
+		Rules:  []serverless.TrafficFilterRule{{Source: "1.1.1.1"}, {Source: "2.2.2.2"}},
+	}
+
+	t.Run("rule blocks", func(t *testing.T) {
+		prior := testModel()
+		prior.Rules = []TrafficFilterRuleModel{
+			{Source: types.StringValue("1.1.1.1"), Description: types.StringValue("  ")},
+			{Source: types.StringValue("2.2.2.2"), Description: types.StringNull()},
+		}
+		require.Equal(t, &[]serverless.TrafficFilterRule{{Source: "1.1.1.1"}, {Source: "2.2.2.2"}}, apiRules(prior.Rules, false))
+
+		model, diags := modelFromResponse(ctx, info, nil, prior)
+		require.False(t, diags.HasError(), diags)
+		require.Equal(t, types.StringValue("  "), model.Rules[0].Description)
+		require.True(t, model.Rules[1].Description.IsNull())
+	})
+
+	t.Run("sources", func(t *testing.T) {
+		prior := sourcesModel([]string{"1.1.1.1", "2.2.2.2"}, map[string]string{"1.1.1.1": "  "})
+		rules, diags := prior.ruleModels(ctx)
+		require.False(t, diags.HasError(), diags)
+		require.Nil(t, (*apiRules(rules, false))[0].Description)
+
+		model, diags := modelFromResponse(ctx, info, nil, prior)
+		require.False(t, diags.HasError(), diags)
+		require.Equal(t, prior.RuleDescriptions, model.RuleDescriptions)
+	})
+}
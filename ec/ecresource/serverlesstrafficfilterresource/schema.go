@@ -20,29 +20,61 @@ package serverlesstrafficfilterresource
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
 )
 
+// timeoutOpts selects the configurable timeouts, deletions being the only operation waiting on the API.
+var timeoutOpts = timeouts.Opts{Delete: true}
+
 type TrafficFilterModel struct {
-	ID               types.String             `tfsdk:"id"`
-	Name             types.String             `tfsdk:"name"`
-	Type             types.String             `tfsdk:"type"`
-	Region           types.String             `tfsdk:"region"`
-	Description      types.String             `tfsdk:"description"`
-	IncludeByDefault types.Bool               `tfsdk:"include_by_default"`
-	Rules            []TrafficFilterRuleModel `tfsdk:"rule"`
+	ID                      types.String             `tfsdk:"id"`
+	Name                    types.String             `tfsdk:"name"`
+	Type                    types.String             `tfsdk:"type"`
+	Region                  types.String             `tfsdk:"region"`
+	Description             types.String             `tfsdk:"description"`
+	IncludeByDefault        types.Bool               `tfsdk:"include_by_default"`
+	Sources                 types.Set                `tfsdk:"sources"`
+	RuleDescriptions        types.Map                `tfsdk:"rule_descriptions"`
+	RuleDescriptionTemplate types.String             `tfsdk:"rule_description_template"`
+	RuleSources             types.Set                `tfsdk:"rule_sources"`
+	AssociationCount        types.Int64              `tfsdk:"association_count"`
+	AppliedRulesCount       types.Int64              `tfsdk:"applied_rules_count"`
+	ReplaceOnRulesChange    types.Bool               `tfsdk:"replace_on_rules_change"`
+	MinRules                types.Int64              `tfsdk:"min_rules"`
+	ManageMarker            types.Bool               `tfsdk:"manage_marker"`
+	WaitForDeletion         types.Bool               `tfsdk:"wait_for_deletion"`
+	SkipClientValidation    types.Bool               `tfsdk:"skip_client_validation"`
+	Timeouts                types.Object             `tfsdk:"timeouts"`
+	Rules                   []TrafficFilterRuleModel `tfsdk:"rule"`
 }
 
 type TrafficFilterRuleModel struct {
 	Source      types.String `tfsdk:"source"`
 	Description types.String `tfsdk:"description"`
+	SourceKind  types.String `tfsdk:"source_kind"`
+}
+
+var ruleAttrTypes = map[string]attr.Type{
+	"source":      types.StringType,
+	"description": types.StringType,
+	"source_kind": types.StringType,
 }
 
 func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -75,20 +107,99 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 				},
 			},
 			"include_by_default": schema.BoolAttribute{
-				Description: "Indicates that the traffic filter should be automatically included in new projects (Defaults to false)",
+				Description: "Indicates that the traffic filter should be automatically included in new projects. If unset, the value currently set on the traffic filter is kept, and new traffic filters get the value applied by the API",
 				Optional:    true,
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"description": schema.StringAttribute{
-				Description: "Traffic filter description",
+				Description: "Traffic filter description. If unset, the default_filter_description of the provider is used if set, otherwise the description applied by the API when creating the traffic filter, such as `Created by ...`, is ignored",
+				Optional:    true,
+				Computed:    true,
+			},
+			"sources": schema.SetAttribute{
+				Description: "Set of traffic filter sources: IP addresses, CIDR masks, or VPC endpoint IDs. An alternative to rule blocks, which can't be used together with them",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplaceIf(replaceOnRulesChangeSet, replaceOnRulesChangeDesc, replaceOnRulesChangeDesc),
+				},
+			},
+			"rule_descriptions": schema.MapAttribute{
+				Description: "Descriptions of the rules defined by the sources attribute, keyed by source. Every key must be part of the sources attribute. Whitespace-only descriptions are treated as no description and aren't sent to the API",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIf(replaceOnRulesChangeMap, replaceOnRulesChangeDesc, replaceOnRulesChangeDesc),
+				},
+			},
+			"rule_sources": schema.SetAttribute{
+				Description: "Set of the sources of all rules, however they are defined. Useful to reference the allowed sources, e.g. in firewall documentation",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"association_count": schema.Int64Attribute{
+				Description: "Number of serverless projects the traffic filter is associated with. Unless the API reports it, counting requires listing all projects of the organization, so it's only refreshed when the traffic filter is created, updated or imported and may be stale otherwise",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"applied_rules_count": schema.Int64Attribute{
+				Description: "Number of rules of the traffic filter as reported by the API. Comparing it with the number of configured rules, e.g. in a postcondition, reveals rules added or removed outside of Terraform",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"replace_on_rules_change": schema.BoolAttribute{
+				Description: "Replace the traffic filter whenever its rules change, instead of updating it in place. Defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"manage_marker": schema.BoolAttribute{
+				Description: "Append `[terraform]` to the description of the traffic filter, showing in the UI that it's managed by Terraform. The marker isn't part of the description attribute. Defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"min_rules": schema.Int64Attribute{
+				Description: "Minimum number of rules of the traffic filter. Plans reducing the number of rules below this minimum fail, unless the traffic filter already had fewer rules",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					minRules{},
+				},
+			},
+			"wait_for_deletion": schema.BoolAttribute{
+				Description: "Wait for the traffic filter to be fully removed when destroying it, for up to the delete timeout, e.g. so that a traffic filter with the same name can be created right away. Deletions the API reports as asynchronous are always waited for. Defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"skip_client_validation": schema.BoolAttribute{
+				Description: "Skip the provider's checks of the rule sources, sending them to the API as configured, e.g. to use sources of a kind the provider doesn't know about yet. IP addresses, CIDR masks and endpoint IDs are still normalized. Defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"timeouts": timeouts.Attributes(timeoutOpts),
+			"rule_description_template": schema.StringAttribute{
+				Description: "Template of the descriptions of the rules defined by the sources attribute, which don't have an entry in rule_descriptions. It's rendered with Go's text/template, the source of the rule is available as `{{.Source}}`",
 				Optional:    true,
 			},
 		},
 		Blocks: map[string]schema.Block{
 			"rule": schema.SetNestedBlock{
-				Description: "Set of rules, which the traffic filter is made of.",
-				Validators:  []validator.Set{setvalidator.SizeAtLeast(1)},
+				Description: "Set of rules, which the traffic filter is made of. At least one rule is required, unless the sources attribute is used instead.",
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplaceIf(replaceOnRulesChangeSet, replaceOnRulesChangeDesc, replaceOnRulesChangeDesc),
+				},
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"source": schema.StringAttribute{
@@ -96,9 +207,13 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 							Required:    true,
 						},
 						"description": schema.StringAttribute{
-							Description: "Description of this individual rule",
+							Description: "Description of this individual rule. Whitespace-only descriptions are treated as no description and aren't sent to the API",
 							Optional:    true,
 						},
+						"source_kind": schema.StringAttribute{
+							Description: "Kind of the source, detected by the provider: `ip` for IP addresses and CIDR masks, `vpce` for AWS VPC endpoint IDs, `endpoint_guid` for Azure private endpoint GUIDs, or `other`",
+							Computed:    true,
+						},
 					},
 				},
 			},
@@ -106,6 +221,23 @@ func (r *Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *res
 	}
 }
 
+const replaceOnRulesChangeDesc = "Requires replacement of the traffic filter if replace_on_rules_change is set."
+
+func replaceOnRulesChangeSet(ctx context.Context, req planmodifier.SetRequest, resp *setplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace, resp.Diagnostics = replaceOnRulesChange(ctx, req.Config)
+}
+
+func replaceOnRulesChangeMap(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace, resp.Diagnostics = replaceOnRulesChange(ctx, req.Config)
+}
+
+// replaceOnRulesChange tells whether changed rules should replace the traffic filter.
+func replaceOnRulesChange(ctx context.Context, config tfsdk.Config) (bool, diag.Diagnostics) {
+	var replace types.Bool
+	diags := config.GetAttribute(ctx, path.Root("replace_on_rules_change"), &replace)
+	return replace.ValueBool(), diags
+}
+
 func stringValue(s string) types.String {
 	return types.StringValue(s)
 }
@@ -113,3 +245,21 @@ func stringValue(s string) types.String {
 func boolValue(b bool) types.Bool {
 	return types.BoolValue(b)
 }
+
+// optionalString returns nil for unset and empty strings, since the API
+// distinguishes a missing description from an empty one.
+func optionalString(s types.String) *string {
+	if s.ValueString() == "" {
+		return nil
+	}
+	return s.ValueStringPointer()
+}
+
+// optionalBool returns nil for unset and unknown values, so that the API keeps
+// its current value, or applies its default to new traffic filters.
+func optionalBool(b types.Bool) *bool {
+	if b.IsNull() || b.IsUnknown() {
+		return nil
+	}
+	return b.ValueBoolPointer()
+}
@@ -0,0 +1,156 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+)
+
+// planBool mimics the framework planning a computed bool attribute which isn't set in the config.
+func planBool(t *testing.T, attributeName string, state tfsdk.State) types.Bool {
+	attribute, ok := testSchema(t).Schema.Attributes[attributeName].(schema.BoolAttribute)
+	require.True(t, ok)
+	require.Nil(t, attribute.Default, "a default would override the state value")
+
+	var stateValue types.Bool
+	require.False(t, state.GetAttribute(context.Background(), path.Root(attributeName), &stateValue).HasError())
+
+	planValue := types.BoolUnknown()
+	for _, modifier := range attribute.PlanModifiers {
+		resp := planmodifier.BoolResponse{PlanValue: planValue}
+		modifier.PlanModifyBool(context.Background(), planmodifier.BoolRequest{
+			Path:        path.Root(attributeName),
+			State:       state,
+			ConfigValue: types.BoolNull(),
+			StateValue:  stateValue,
+			PlanValue:   planValue,
+		}, &resp)
+		require.False(t, resp.Diagnostics.HasError())
+		planValue = resp.PlanValue
+	}
+	return planValue
+}
+
+func TestIncludeByDefault_ImportedTrueHasNoDiffWhenOmitted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+		JSON200: &serverless.TrafficFilterInfo{
+			Id:               "filter-id",
+			Name:             "my-filter",
+			Region:           "us-east-1",
+			Type:             "ip",
+			IncludeByDefault: true,
+			Rules:            []serverless.TrafficFilterRule{{Source: "1.1.1.1"}},
+		},
+		Body:         []byte(`{"association_count":0}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).Times(2)
+
+	r := &Resource{client: mockClient}
+
+	// Import only checks and sets the ID, the remaining attributes are populated by the subsequent read.
+	schemaResp := testSchema(t)
+	importResp := resource.ImportStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)},
+	}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "filter-id"}, &importResp)
+	require.False(t, importResp.Diagnostics.HasError())
+
+	req := resource.ReadRequest{State: importResp.State}
+	initPrivateState(t, &req)
+	resp := resource.ReadResponse{State: importResp.State}
+	initPrivateState(t, &resp)
+	r.Read(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var state TrafficFilterModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	require.Equal(t, types.BoolValue(true), state.IncludeByDefault)
+
+	require.Equal(t, state.IncludeByDefault, planBool(t, "include_by_default", resp.State))
+}
+
+func TestIncludeByDefault_UnknownOnCreateWhenOmitted(t *testing.T) {
+	schemaResp := testSchema(t)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil)}
+	require.True(t, planBool(t, "include_by_default", state).IsUnknown())
+}
+
+func TestReplaceOnRulesChange(t *testing.T) {
+	tests := []struct {
+		name            string
+		replace         types.Bool
+		expectedReplace bool
+	}{
+		{name: "replaces with the flag set", replace: types.BoolValue(true), expectedReplace: true},
+		{name: "updates in place without the flag", replace: types.BoolValue(false), expectedReplace: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			stateModel := testModel()
+			stateModel.ID = types.StringValue("filter-id")
+			stateModel.ReplaceOnRulesChange = tt.replace
+			planModel := stateModel
+			planModel.Rules = []TrafficFilterRuleModel{{Source: types.StringValue("2.2.2.2"), Description: types.StringNull()}}
+
+			state := testState(t, stateModel)
+			plan := testPlan(t, planModel)
+			var stateValue, planValue types.Set
+			require.False(t, state.GetAttribute(ctx, path.Root("rule"), &stateValue).HasError())
+			require.False(t, plan.GetAttribute(ctx, path.Root("rule"), &planValue).HasError())
+
+			block, ok := testSchema(t).Schema.Blocks["rule"].(schema.SetNestedBlock)
+			require.True(t, ok)
+
+			resp := planmodifier.SetResponse{PlanValue: planValue}
+			for _, modifier := range block.PlanModifiers {
+				modifier.PlanModifySet(ctx, planmodifier.SetRequest{
+					Path:       path.Root("rule"),
+					Config:     tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+					State:      state,
+					Plan:       plan,
+					StateValue: stateValue,
+					PlanValue:  planValue,
+				}, &resp)
+			}
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			require.Equal(t, tt.expectedReplace, resp.RequiresReplace)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Kinds of traffic filter rule sources.
+const (
+	sourceKindIP           = "ip"
+	sourceKindVPCE         = "vpce"
+	sourceKindEndpointGUID = "endpoint_guid"
+	sourceKindOther        = "other"
+)
+
+var (
+	vpceIDRegex = regexp.MustCompile(`^vpce-[0-9a-f]+$`)
+	guidRegex   = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// detectSourceKind classifies a rule source as an IP address or CIDR mask, an
+// AWS VPC endpoint ID, an Azure private endpoint GUID, or anything else.
+func detectSourceKind(source string) string {
+	normalized := normalizeSource(source)
+	switch {
+	case isIPSource(normalized):
+		return sourceKindIP
+	case vpceIDRegex.MatchString(normalized):
+		return sourceKindVPCE
+	case guidRegex.MatchString(normalized):
+		return sourceKindEndpointGUID
+	default:
+		return sourceKindOther
+	}
+}
+
+func isIPSource(source string) bool {
+	if _, err := netip.ParsePrefix(source); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddr(source)
+	return err == nil
+}
+
+// normalizeSource returns the canonical spelling of a rule source: IP addresses
+// and CIDR masks in their shortest form, endpoint IDs in lower case.
+func normalizeSource(source string) string {
+	source = strings.TrimSpace(source)
+	if prefix, err := netip.ParsePrefix(source); err == nil {
+		return prefix.String()
+	}
+	if addr, err := netip.ParseAddr(source); err == nil {
+		return addr.String()
+	}
+	return strings.ToLower(source)
+}
+
+// withPriorSpelling replaces the sources of the given rules by the spelling
+// used in the prior model, if they only differ in their normalization. This
+// keeps the state consistent with the configuration.
+func withPriorSpelling(rules []TrafficFilterRuleModel, prior TrafficFilterModel) []TrafficFilterRuleModel {
+	spellings := map[string]types.String{}
+	for _, rule := range prior.Rules {
+		if !rule.Source.IsUnknown() && !rule.Source.IsNull() {
+			spellings[normalizeSource(rule.Source.ValueString())] = rule.Source
+		}
+	}
+	for _, elem := range prior.Sources.Elements() {
+		if source, ok := elem.(types.String); ok && !source.IsUnknown() && !source.IsNull() {
+			spellings[normalizeSource(source.ValueString())] = source
+		}
+	}
+
+	result := make([]TrafficFilterRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		if spelling, ok := spellings[normalizeSource(rule.Source.ValueString())]; ok {
+			rule.Source = spelling
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// typeMismatchWarnings warns about the rules of a traffic filter read from the API whose sources
+// don't fit its type, e.g. an ip traffic filter with VPC endpoint IDs, which likely is a data issue.
+func typeMismatchWarnings(filterType string, rules []TrafficFilterRuleModel) diag.Diagnostics {
+	var problems []string
+	for _, rule := range rules {
+		if msg := sourceError(filterType, rule.Source.ValueString()); msg != "" {
+			problems = append(problems, msg)
+		}
+	}
+
+	var diags diag.Diagnostics
+	if len(problems) > 0 {
+		diags.AddAttributeWarning(
+			path.Root("type"),
+			"Traffic filter type doesn't match its rules",
+			fmt.Sprintf("The API reports the type %s, but the rule sources don't match it: %s. Check the traffic filter in the Elastic Cloud console.",
+				filterType, strings.Join(problems, "; ")),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,176 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/timeouts"
+)
+
+func TestDetectSourceKind(t *testing.T) {
+	tests := map[string]string{
+		"1.1.1.1":                              sourceKindIP,
+		"10.0.0.0/8":                           sourceKindIP,
+		" 192.168.0.1 ":                        sourceKindIP,
+		"2001:DB8::/32":                        sourceKindIP,
+		"vpce-0123456789abcdef0":               sourceKindVPCE,
+		"VPCE-0123456789ABCDEF0":               sourceKindVPCE,
+		"d6a1a7e2-9f0c-4b62-8c57-8d5c2a3e0f11": sourceKindEndpointGUID,
+		"18446744073709551615":                 sourceKindOther,
+		"10.0.0.0/33":                          sourceKindOther,
+	}
+	for source, expected := range tests {
+		require.Equal(t, expected, detectSourceKind(source), source)
+	}
+}
+
+func TestNormalizeSource(t *testing.T) {
+	tests := map[string]string{
+		"1.1.1.1":                              "1.1.1.1",
+		" 10.0.0.0/8\n":                        "10.0.0.0/8",
+		"2001:0DB8:0000::/32":                  "2001:db8::/32",
+		"VPCE-0123456789ABCDEF0":               "vpce-0123456789abcdef0",
+		"D6A1A7E2-9F0C-4B62-8C57-8D5C2A3E0F11": "d6a1a7e2-9f0c-4b62-8c57-8d5c2a3e0f11",
+	}
+	for source, expected := range tests {
+		require.Equal(t, expected, normalizeSource(source), source)
+	}
+}
+
+func TestApiRules_NormalizesSources(t *testing.T) {
+	rules := apiRules([]TrafficFilterRuleModel{
+		{Source: types.StringValue("2001:0DB8::1"), Description: types.StringNull()},
+		{Source: types.StringValue("VPCE-0ABC"), Description: types.StringNull()},
+	}, false)
+	require.Equal(t, &[]serverless.TrafficFilterRule{{Source: "2001:db8::1"}, {Source: "vpce-0abc"}}, rules)
+}
+
+func TestApiRules_SkipClientValidation(t *testing.T) {
+	rules := apiRules([]TrafficFilterRuleModel{
+		{Source: types.StringValue("2001:0DB8::1"), Description: types.StringNull()},
+		{Source: types.StringValue("VPCE-0ABC"), Description: types.StringNull()},
+		{Source: types.StringValue("PSC:Projects/My-Project"), Description: types.StringNull()},
+	}, true)
+	require.Equal(t, &[]serverless.TrafficFilterRule{
+		{Source: "2001:db8::1"},
+		{Source: "vpce-0abc"},
+		{Source: "PSC:Projects/My-Project"},
+	}, rules)
+}
+
+func TestModelFromResponse_ClassifiesMixedSources(t *testing.T) {
+	prior := testModel()
+	prior.Type = types.StringValue("vpce")
+	prior.Rules = []TrafficFilterRuleModel{
+		{Source: types.StringValue("VPCE-0ABC"), Description: types.StringNull()},
+		{Source: types.StringValue("d6a1a7e2-9f0c-4b62-8c57-8d5c2a3e0f11"), Description: types.StringNull()},
+		{Source: types.StringValue("18446744073709551615"), Description: types.StringNull()},
+	}
+
+	info := &serverless.TrafficFilterInfo{
+		Id:     "filter-id",
+		Name:   "my-filter",
+		Region: "us-east-1",
+		Type:   "vpce",
+		Rules: []serverless.TrafficFilterRule{
+			{Source: "vpce-0abc"},
+			{Source: "d6a1a7e2-9f0c-4b62-8c57-8d5c2a3e0f11"},
+			{Source: "18446744073709551615"},
+			{Source: "10.0.0.0/8"},
+		},
+	}
+
+	model, diags := modelFromResponse(context.Background(), info, nil, prior)
+	require.False(t, diags.HasError(), diags)
+
+	kinds := map[string]string{}
+	for _, rule := range model.Rules {
+		kinds[rule.Source.ValueString()] = rule.SourceKind.ValueString()
+	}
+	// The configured spelling of the vpce ID is kept, since the API returns it normalized.
+	require.Equal(t, map[string]string{
+		"VPCE-0ABC":                            sourceKindVPCE,
+		"d6a1a7e2-9f0c-4b62-8c57-8d5c2a3e0f11": sourceKindEndpointGUID,
+		"18446744073709551615":                 sourceKindOther,
+		"10.0.0.0/8":                           sourceKindIP,
+	}, kinds)
+}
+
+func TestRead_WarnsAboutImportedTypeMismatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		expectWarned bool
+	}{
+		{name: "vpce source in an ip traffic filter", source: "vpce-0123abcd", expectWarned: true},
+		{name: "ip source in an ip traffic filter", source: "1.1.1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().GetTrafficFilterWithResponse(gomock.Any(), "filter-id").Return(&serverless.GetTrafficFilterResponse{
+				JSON200: &serverless.TrafficFilterInfo{
+					Id:     "filter-id",
+					Name:   "my-filter",
+					Region: "us-east-1",
+					Type:   "ip",
+					Rules:  []serverless.TrafficFilterRule{{Source: tt.source}},
+				},
+				Body:         []byte(`{"association_count":0}`),
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+
+			// State of an imported traffic filter, which only has its ID.
+			state := testState(t, TrafficFilterModel{
+				ID:               types.StringValue("filter-id"),
+				Sources:          types.SetNull(types.StringType),
+				RuleDescriptions: types.MapNull(types.StringType),
+				RuleSources:      types.SetNull(types.StringType),
+				Timeouts:         types.ObjectNull(timeouts.AttrTypes(timeoutOpts)),
+			})
+			req := resource.ReadRequest{State: state}
+			initPrivateState(t, &req)
+			resp := resource.ReadResponse{State: state}
+			initPrivateState(t, &resp)
+			(&Resource{client: mockClient}).Read(ctx, req, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			if !tt.expectWarned {
+				require.Empty(t, resp.Diagnostics.Warnings())
+				return
+			}
+			require.Len(t, resp.Diagnostics.Warnings(), 1)
+			warning := resp.Diagnostics.Warnings()[0]
+			require.Equal(t, "Traffic filter type doesn't match its rules", warning.Summary())
+			require.Contains(t, warning.Detail(), `"vpce-0123abcd" is not a valid IP address or CIDR mask`)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ruleTemplateData is the data available to rule_description_template.
+type ruleTemplateData struct {
+	Source string
+}
+
+// ruleDescriptionTemplate is the parsed rule_description_template, rendering
+// the descriptions of the rules defined by the sources attribute which don't
+// have a rule_descriptions entry. A nil template renders no descriptions.
+type ruleDescriptionTemplate struct {
+	tmpl *template.Template
+}
+
+func parseRuleDescriptionTemplate(s types.String) (*ruleDescriptionTemplate, error) {
+	if s.IsNull() || s.IsUnknown() {
+		return nil, nil
+	}
+
+	// Templates get no functions beyond the builtin ones, and fail on unknown
+	// fields instead of rendering them as "<no value>".
+	tmpl, err := template.New("rule_description_template").Option("missingkey=error").Parse(s.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ruleDescriptionTemplate{tmpl: tmpl}
+	// Catch execution errors, like references to unknown fields, before applying.
+	if _, err := t.render("0.0.0.0/0"); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *ruleDescriptionTemplate) render(source string) (string, error) {
+	if t == nil {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, ruleTemplateData{Source: source}); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
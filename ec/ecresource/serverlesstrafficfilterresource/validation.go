@@ -0,0 +1,133 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits of the serverless traffic filter API.
+const (
+	maxNameLength        = 128
+	maxRegionLength      = 32
+	maxDescriptionLength = 512
+)
+
+// TrafficFilterSpec is a traffic filter definition, as written in the
+// configuration of the ec_serverless_traffic_filter resource.
+type TrafficFilterSpec struct {
+	Name             string
+	Type             string
+	Region           string
+	Description      string
+	Sources          []string
+	RuleDescriptions map[string]string
+	Rules            []TrafficFilterRuleSpec
+}
+
+type TrafficFilterRuleSpec struct {
+	Source      string
+	Description string
+}
+
+// ValidateTrafficFilter checks a traffic filter definition without calling the
+// API, returning a message for every problem found.
+func ValidateTrafficFilter(spec TrafficFilterSpec) []string {
+	var problems []string
+
+	if n := utf8.RuneCountInString(spec.Name); n == 0 || n > maxNameLength {
+		problems = append(problems, fmt.Sprintf("name: must be between 1 and %d characters long", maxNameLength))
+	}
+	if spec.Type != "ip" && spec.Type != "vpce" {
+		problems = append(problems, fmt.Sprintf("type: must be ip or vpce, got %q", spec.Type))
+	}
+	if n := utf8.RuneCountInString(spec.Region); n == 0 || n > maxRegionLength {
+		problems = append(problems, fmt.Sprintf("region: must be between 1 and %d characters long", maxRegionLength))
+	}
+	if utf8.RuneCountInString(spec.Description) > maxDescriptionLength {
+		problems = append(problems, fmt.Sprintf("description: must be at most %d characters long", maxDescriptionLength))
+	}
+
+	usesSources := spec.Sources != nil || spec.RuleDescriptions != nil
+	switch {
+	case len(spec.Rules) > 0 && usesSources:
+		problems = append(problems, "sources: rule blocks can't be used together with the sources and rule_descriptions attributes")
+	case len(spec.Rules) == 0 && len(spec.Sources) == 0:
+		problems = append(problems, "sources: at least one rule is required, defined either by a rule block or by the sources attribute")
+	}
+
+	for i, rule := range spec.Rules {
+		if msg := sourceError(spec.Type, rule.Source); msg != "" {
+			problems = append(problems, fmt.Sprintf("rule[%d].source: %s", i, msg))
+		}
+	}
+
+	known := make(map[string]bool, len(spec.Sources))
+	for _, source := range spec.Sources {
+		known[source] = true
+		if msg := sourceError(spec.Type, source); msg != "" {
+			problems = append(problems, fmt.Sprintf("sources: %s", msg))
+		}
+	}
+	for _, source := range sortedKeys(spec.RuleDescriptions) {
+		if !known[source] {
+			problems = append(problems, fmt.Sprintf("rule_descriptions[%q]: %s is not part of the sources attribute", source, source))
+		}
+	}
+
+	return problems
+}
+
+// sourceError checks that a rule source fits the type of the traffic filter,
+// returning an empty string if it does. Sources of unknown filter types are
+// not checked.
+func sourceError(filterType, source string) string {
+	isIP := detectSourceKind(source) == sourceKindIP
+
+	switch {
+	case filterType == "ip" && !isIP:
+		return fmt.Sprintf("%q is not a valid IP address or CIDR mask", source)
+	case filterType == "vpce" && isIP:
+		return fmt.Sprintf("%q is an IP address or CIDR mask, which can't be used in a vpce traffic filter", source)
+	}
+	return ""
+}
+
+// MergeRules returns the union of the given rules, keeping a single rule per
+// source once normalized. The spelling and position of the first rule with a
+// given source are kept, along with the first non-empty description.
+func MergeRules(ruleSets ...[]TrafficFilterRuleSpec) []TrafficFilterRuleSpec {
+	var result []TrafficFilterRuleSpec
+	indexes := map[string]int{}
+	for _, rules := range ruleSets {
+		for _, rule := range rules {
+			source := normalizeSource(rule.Source)
+			i, ok := indexes[source]
+			if !ok {
+				indexes[source] = len(result)
+				result = append(result, rule)
+				continue
+			}
+			if result[i].Description == "" {
+				result[i].Description = rule.Description
+			}
+		}
+	}
+	return result
+}
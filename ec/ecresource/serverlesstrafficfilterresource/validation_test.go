@@ -0,0 +1,153 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlesstrafficfilterresource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validSpec() TrafficFilterSpec {
+	return TrafficFilterSpec{
+		Name:   "my-filter",
+		Type:   "ip",
+		Region: "us-east-1",
+		Rules:  []TrafficFilterRuleSpec{{Source: "1.1.1.1"}, {Source: "10.0.0.0/8", Description: "vpn"}},
+	}
+}
+
+func TestValidateTrafficFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     func(s *TrafficFilterSpec)
+		expected []string
+	}{
+		{
+			name: "valid rule blocks",
+			spec: func(s *TrafficFilterSpec) {},
+		},
+		{
+			name: "valid sources",
+			spec: func(s *TrafficFilterSpec) {
+				s.Rules = nil
+				s.Sources = []string{"1.1.1.1"}
+				s.RuleDescriptions = map[string]string{"1.1.1.1": "office"}
+			},
+		},
+		{
+			name: "valid vpce filter",
+			spec: func(s *TrafficFilterSpec) {
+				s.Type = "vpce"
+				s.Rules = []TrafficFilterRuleSpec{{Source: "vpce-00000000000000000"}}
+			},
+		},
+		{
+			name: "missing name and unknown type",
+			spec: func(s *TrafficFilterSpec) {
+				s.Name = ""
+				s.Type = "ipv6"
+			},
+			expected: []string{
+				"name: must be between 1 and 128 characters long",
+				`type: must be ip or vpce, got "ipv6"`,
+			},
+		},
+		{
+			name: "overlong region and description",
+			spec: func(s *TrafficFilterSpec) {
+				s.Region = strings.Repeat("r", 33)
+				s.Description = strings.Repeat("d", 513)
+			},
+			expected: []string{
+				"region: must be between 1 and 32 characters long",
+				"description: must be at most 512 characters long",
+			},
+		},
+		{
+			name: "no rules",
+			spec: func(s *TrafficFilterSpec) {
+				s.Rules = nil
+			},
+			expected: []string{"sources: at least one rule is required, defined either by a rule block or by the sources attribute"},
+		},
+		{
+			name: "rule blocks and sources",
+			spec: func(s *TrafficFilterSpec) {
+				s.Sources = []string{"2.2.2.2"}
+			},
+			expected: []string{"sources: rule blocks can't be used together with the sources and rule_descriptions attributes"},
+		},
+		{
+			name: "invalid CIDR masks",
+			spec: func(s *TrafficFilterSpec) {
+				s.Rules = []TrafficFilterRuleSpec{{Source: "1.1.1.1"}, {Source: "10.0.0.0/33"}}
+			},
+			expected: []string{`rule[1].source: "10.0.0.0/33" is not a valid IP address or CIDR mask`},
+		},
+		{
+			name: "ip source in a vpce filter",
+			spec: func(s *TrafficFilterSpec) {
+				s.Type = "vpce"
+				s.Rules = nil
+				s.Sources = []string{"1.1.1.1"}
+			},
+			expected: []string{`sources: "1.1.1.1" is an IP address or CIDR mask, which can't be used in a vpce traffic filter`},
+		},
+		{
+			name: "description for an unknown source",
+			spec: func(s *TrafficFilterSpec) {
+				s.Rules = nil
+				s.Sources = []string{"1.1.1.1"}
+				s.RuleDescriptions = map[string]string{"2.2.2.2": "office"}
+			},
+			expected: []string{`rule_descriptions["2.2.2.2"]: 2.2.2.2 is not part of the sources attribute`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			tt.spec(&spec)
+			require.Equal(t, tt.expected, ValidateTrafficFilter(spec))
+		})
+	}
+}
+
+func TestMergeRules(t *testing.T) {
+	a := []TrafficFilterRuleSpec{
+		{Source: "1.1.1.1", Description: ""},
+		{Source: "10.0.0.0/8", Description: "office"},
+		{Source: "vpce-0123456789abcdef0", Description: "vpc"},
+	}
+	b := []TrafficFilterRuleSpec{
+		{Source: "1.1.1.1", Description: "vpn"},
+		{Source: " 10.0.0.0/8", Description: "datacenter"},
+		{Source: "VPCE-0123456789ABCDEF0", Description: ""},
+		{Source: "2.2.2.2", Description: "partner"},
+	}
+
+	require.Equal(t, []TrafficFilterRuleSpec{
+		{Source: "1.1.1.1", Description: "vpn"},
+		{Source: "10.0.0.0/8", Description: "office"},
+		{Source: "vpce-0123456789abcdef0", Description: "vpc"},
+		{Source: "2.2.2.2", Description: "partner"},
+	}, MergeRules(a, b))
+	require.Nil(t, MergeRules(nil, nil))
+}
@@ -19,16 +19,41 @@ package internal
 
 import (
 	"fmt"
+	"net/netip"
+	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 
 	"github.com/elastic/cloud-sdk-go/pkg/api"
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
 )
 
+// ProviderClients holds the API clients created when configuring the provider. They're shared
+// by all resources and data sources, so that connections as well as the retry and rate limiting
+// state of the serverless client are shared too.
 type ProviderClients struct {
 	Stateful   *api.API
 	Serverless serverless.ClientWithResponsesInterface
+	// AllowedSourceCIDRs restricts the IP sources of serverless traffic filter rules,
+	// nil if the provider doesn't restrict them.
+	AllowedSourceCIDRs []netip.Prefix
+	// NamePattern must be matched by the names of serverless traffic filters,
+	// nil if the provider doesn't restrict them.
+	NamePattern *regexp.Regexp
+	// DefaultFilterDesc is the description of serverless traffic filters
+	// without one, empty if they don't get a default description.
+	DefaultFilterDesc string
+	// MutationWindow restricts the changes of serverless traffic filter
+	// resources to a daily time range, nil if they're always allowed.
+	MutationWindow *util.MutationWindow
+	// DiagnosticsJSONLog enables logging the diagnostics of serverless traffic
+	// filter resources as JSON lines, see transport.LogDiagnosticsJSON.
+	DiagnosticsJSONLog bool
+	// FilterLists holds the listings of serverless traffic filters used to resolve their
+	// names, which the traffic filter resources invalidate when changing them.
+	FilterLists *serverlessutil.FilterListCache
 }
 
 // ConvertProviderData is a helper function for DataSource.Configure and Resource.Configure implementations
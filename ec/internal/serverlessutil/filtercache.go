@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+)
+
+// FilterListCacheTTL is how long a listing of the traffic filters of a region is reused, sparing
+// redundant list calls when several traffic filters of a region are resolved by name.
+const FilterListCacheTTL = 30 * time.Second
+
+type cachedFilterList struct {
+	filters  []serverless.TrafficFilterInfo
+	listedAt time.Time
+}
+
+// FilterListCache holds recent listings of the traffic filters of a region. Resources changing
+// traffic filters invalidate the listing of their region. A nil cache caches nothing.
+type FilterListCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	listings map[string]cachedFilterList
+}
+
+func NewFilterListCache(ttl time.Duration) *FilterListCache {
+	return &FilterListCache{
+		ttl:      ttl,
+		now:      time.Now,
+		listings: map[string]cachedFilterList{},
+	}
+}
+
+func (c *FilterListCache) get(region string) ([]serverless.TrafficFilterInfo, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.listings[region]
+	if !ok || c.now().Sub(cached.listedAt) >= c.ttl {
+		delete(c.listings, region)
+		return nil, false
+	}
+	return append([]serverless.TrafficFilterInfo{}, cached.filters...), true
+}
+
+func (c *FilterListCache) put(region string, filters []serverless.TrafficFilterInfo) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.listings[region] = cachedFilterList{filters: append([]serverless.TrafficFilterInfo{}, filters...), listedAt: c.now()}
+}
+
+// Invalidate drops the listing of the given region, e.g. after a traffic filter of the region changed.
+func (c *FilterListCache) Invalidate(region string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.listings, region)
+}
@@ -0,0 +1,222 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package serverlessutil reads serverless projects and traffic filters the
+// same way for all resources and data sources of the provider.
+package serverlessutil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+// Project holds the project attributes relevant to traffic filters.
+type Project struct {
+	Name           string
+	RegionID       string
+	ETag           string
+	TrafficFilters []serverless.TrafficFilter
+}
+
+// ProjectDetails describes where a serverless project runs, e.g. to place traffic filters in the same region.
+type ProjectDetails struct {
+	Type          string
+	Name          string
+	RegionID      string
+	CloudProvider types.String
+}
+
+// LookupProject reads a serverless project. If no project type is given, it's
+// detected by looking the project up under each type.
+func LookupProject(ctx context.Context, client serverless.ClientWithResponsesInterface, projectID, projectType string) (ProjectDetails, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if projectType == "" {
+		projectType, diags = DetectProjectType(ctx, client, projectID)
+		if diags.HasError() {
+			return ProjectDetails{}, diags
+		}
+	}
+
+	project, d := FetchProject(ctx, client, projectID, projectType)
+	diags.Append(d...)
+	if diags.HasError() {
+		return ProjectDetails{}, diags
+	}
+
+	return ProjectDetails{
+		Type:          projectType,
+		Name:          project.Name,
+		RegionID:      project.RegionID,
+		CloudProvider: CloudProvider(project.RegionID),
+	}, diags
+}
+
+// DetectProjectType finds the type of a project by looking it up under each of the project types
+func DetectProjectType(ctx context.Context, client serverless.ClientWithResponsesInterface, projectID string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for _, projectType := range []string{"elasticsearch", "observability", "security"} {
+		var statusCode int
+		var err error
+		switch projectType {
+		case "elasticsearch":
+			var resp *serverless.GetElasticsearchProjectResponse
+			if resp, err = client.GetElasticsearchProjectWithResponse(ctx, projectID); err == nil {
+				statusCode = resp.StatusCode()
+			}
+		case "observability":
+			var resp *serverless.GetObservabilityProjectResponse
+			if resp, err = client.GetObservabilityProjectWithResponse(ctx, projectID); err == nil {
+				statusCode = resp.StatusCode()
+			}
+		case "security":
+			var resp *serverless.GetSecurityProjectResponse
+			if resp, err = client.GetSecurityProjectWithResponse(ctx, projectID); err == nil {
+				statusCode = resp.StatusCode()
+			}
+		}
+
+		if err != nil {
+			diags.AddError(util.APIReadFailed, util.OperationDetail("read project", util.RequestErrorDetail(err)))
+			return "", diags
+		}
+
+		switch statusCode {
+		case http.StatusOK:
+			return projectType, diags
+		case http.StatusNotFound:
+			continue
+		default:
+			diags.AddError(
+				util.APIReadFailed,
+				util.OperationDetail("read project", fmt.Sprintf("Looking up %s project %s failed with: %d %s", projectType, projectID, statusCode, http.StatusText(statusCode))),
+			)
+			return "", diags
+		}
+	}
+
+	diags.AddError(
+		"Project not found",
+		fmt.Sprintf("No elasticsearch, observability or security project with ID %s was found", projectID),
+	)
+	return "", diags
+}
+
+// FetchProject reads the region and the current traffic filters of a project.
+func FetchProject(ctx context.Context, client serverless.ClientWithResponsesInterface, projectID, projectType string) (Project, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	switch projectType {
+	case "elasticsearch":
+		resp, err := client.GetElasticsearchProjectWithResponse(ctx, projectID)
+		if err != nil {
+			diags.AddError(util.APIReadFailed, util.OperationDetail("read project", util.RequestErrorDetail(err)))
+			return Project{}, diags
+		}
+		if resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
+			diags.AddError("Project not found", fmt.Sprintf("Elasticsearch project %s not found", projectID))
+			return Project{}, diags
+		}
+		if resp.JSON200 == nil {
+			diags.AddError(
+				util.APIReadFailed,
+				util.OperationDetail("read project", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+			)
+			return Project{}, diags
+		}
+		return newProject(resp.JSON200.Name, string(resp.JSON200.RegionId), etag(resp.HTTPResponse), resp.JSON200.TrafficFilters), nil
+
+	case "observability":
+		resp, err := client.GetObservabilityProjectWithResponse(ctx, projectID)
+		if err != nil {
+			diags.AddError(util.APIReadFailed, util.OperationDetail("read project", util.RequestErrorDetail(err)))
+			return Project{}, diags
+		}
+		if resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
+			diags.AddError("Project not found", fmt.Sprintf("Observability project %s not found", projectID))
+			return Project{}, diags
+		}
+		if resp.JSON200 == nil {
+			diags.AddError(
+				util.APIReadFailed,
+				util.OperationDetail("read project", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+			)
+			return Project{}, diags
+		}
+		return newProject(resp.JSON200.Name, string(resp.JSON200.RegionId), etag(resp.HTTPResponse), resp.JSON200.TrafficFilters), nil
+
+	case "security":
+		resp, err := client.GetSecurityProjectWithResponse(ctx, projectID)
+		if err != nil {
+			diags.AddError(util.APIReadFailed, util.OperationDetail("read project", util.RequestErrorDetail(err)))
+			return Project{}, diags
+		}
+		if resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
+			diags.AddError("Project not found", fmt.Sprintf("Security project %s not found", projectID))
+			return Project{}, diags
+		}
+		if resp.JSON200 == nil {
+			diags.AddError(
+				util.APIReadFailed,
+				util.OperationDetail("read project", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+			)
+			return Project{}, diags
+		}
+		return newProject(resp.JSON200.Name, string(resp.JSON200.RegionId), etag(resp.HTTPResponse), resp.JSON200.TrafficFilters), nil
+
+	default:
+		diags.AddError("Invalid project type", fmt.Sprintf("Unknown project type: %s", projectType))
+		return Project{}, diags
+	}
+}
+
+// CloudProvider derives the cloud provider from a region ID such as aws-us-east-1.
+func CloudProvider(regionID string) types.String {
+	csp, _, _ := strings.Cut(regionID, "-")
+	switch serverless.CSP(csp) {
+	case serverless.Aws, serverless.Azure, serverless.Gcp:
+		return types.StringValue(csp)
+	}
+	return types.StringNull()
+}
+
+// newProject converts a project read from the API. A project without traffic
+// filters and one whose traffic filters have been explicitly cleared both have
+// an empty, non-nil list of traffic filters: associations only depend on the
+// traffic filters being present, so the distinction doesn't matter for them.
+func newProject(name, regionID, etag string, filters *serverless.TrafficFilters) Project {
+	project := Project{Name: name, RegionID: regionID, ETag: etag, TrafficFilters: []serverless.TrafficFilter{}}
+	if filters != nil {
+		project.TrafficFilters = *filters
+	}
+	return project
+}
+
+func etag(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.Header.Get("ETag")
+}
@@ -0,0 +1,38 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessutil
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudProvider(t *testing.T) {
+	tests := map[string]types.String{
+		"aws-us-east-1":   types.StringValue("aws"),
+		"gcp-us-central1": types.StringValue("gcp"),
+		"azure-eastus":    types.StringValue("azure"),
+		"us-east-1":       types.StringNull(),
+		"":                types.StringNull(),
+	}
+	for regionID, expected := range tests {
+		require.Equal(t, expected, CloudProvider(regionID), regionID)
+	}
+}
@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+// ResolveTrafficFilterName returns the ID of the only traffic filter with the given name in the
+// given region. Listings of the region are reused from the given cache, which may be nil.
+func ResolveTrafficFilterName(ctx context.Context, client serverless.ClientWithResponsesInterface, cache *FilterListCache, name, region string) (string, diag.Diagnostics) {
+	filters, diags := listTrafficFilters(ctx, client, cache, region)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	var ids []string
+	for _, f := range filters {
+		if f.Name == name && f.Region == region {
+			ids = append(ids, f.Id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		diags.AddError(
+			"Traffic filter not found",
+			fmt.Sprintf("No traffic filter named %q exists in region %s", name, region),
+		)
+		return "", diags
+	case 1:
+		return ids[0], diags
+	default:
+		diags.AddError(
+			"Ambiguous traffic filter name",
+			fmt.Sprintf("Found %d traffic filters named %q in region %s: %s. Reference one of them by its ID instead.", len(ids), name, region, strings.Join(ids, ", ")),
+		)
+		return "", diags
+	}
+}
+
+// listTrafficFilters lists the traffic filters of the given region, reusing a recent listing if there is one
+func listTrafficFilters(ctx context.Context, client serverless.ClientWithResponsesInterface, cache *FilterListCache, region string) ([]serverless.TrafficFilterInfo, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if filters, ok := cache.get(region); ok {
+		return filters, diags
+	}
+
+	resp, err := client.ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{Region: &region})
+	if err != nil {
+		diags.AddError(util.APIListFailed, util.OperationDetail("list traffic filters", util.RequestErrorDetail(err)))
+		return nil, diags
+	}
+	if resp.JSON200 == nil {
+		diags.AddError(
+			util.APIListFailed,
+			util.OperationDetail("list traffic filters", util.APIFailureDetail(resp.HTTPResponse, resp.Body)),
+		)
+		return nil, diags
+	}
+
+	cache.put(region, resp.JSON200.Items)
+	return resp.JSON200.Items, diags
+}
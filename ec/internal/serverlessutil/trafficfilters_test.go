@@ -0,0 +1,148 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package serverlessutil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless/mocks"
+	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+)
+
+func TestResolveTrafficFilterName(t *testing.T) {
+	filters := []serverless.TrafficFilterInfo{
+		{Id: "unique-id", Name: "unique", Region: "us-east-1"},
+		{Id: "dup-1", Name: "duplicate", Region: "us-east-1"},
+		{Id: "dup-2", Name: "duplicate", Region: "us-east-1"},
+		{Id: "other-region-id", Name: "other-region", Region: "eu-west-1"},
+	}
+
+	tests := []struct {
+		name          string
+		filterName    string
+		expectedID    string
+		expectedError string
+	}{
+		{
+			name:       "resolves a unique name",
+			filterName: "unique",
+			expectedID: "unique-id",
+		},
+		{
+			name:          "fails on ambiguous names",
+			filterName:    "duplicate",
+			expectedError: "Ambiguous traffic filter name",
+		},
+		{
+			name:          "fails if no filter has the name",
+			filterName:    "missing",
+			expectedError: "Traffic filter not found",
+		},
+		{
+			name:          "ignores filters in other regions",
+			filterName:    "other-region",
+			expectedError: "Traffic filter not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			region := "us-east-1"
+
+			mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+			mockClient.EXPECT().ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{Region: &region}).Return(&serverless.ListTrafficFiltersResponse{
+				JSON200:      &serverless.TrafficFilterList{Items: filters},
+				HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+			}, nil)
+
+			id, diags := ResolveTrafficFilterName(ctx, mockClient, nil, tt.filterName, region)
+
+			if tt.expectedError != "" {
+				require.True(t, diags.HasError())
+				require.Equal(t, tt.expectedError, diags.Errors()[0].Summary())
+				return
+			}
+			require.False(t, diags.HasError(), diags)
+			require.Equal(t, tt.expectedID, id)
+		})
+	}
+}
+
+func TestResolveTrafficFilterName_ListFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	region := "us-east-1"
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{Region: &region}).Return(&serverless.ListTrafficFiltersResponse{
+		Body:         []byte(`{"error":"internal"}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error"},
+	}, nil)
+
+	_, diags := ResolveTrafficFilterName(ctx, mockClient, nil, "unique", region)
+
+	require.True(t, diags.HasError())
+	require.Equal(t, util.APIListFailed, diags.Errors()[0].Summary())
+	require.True(t, strings.HasPrefix(diags.Errors()[0].Detail(), "Failed to list traffic filters."), diags.Errors()[0].Detail())
+}
+
+func TestResolveTrafficFilterName_ReusesRecentListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	region := "us-east-1"
+
+	mockClient := mocks.NewMockClientWithResponsesInterface(ctrl)
+	mockClient.EXPECT().ListTrafficFiltersWithResponse(ctx, &serverless.ListTrafficFiltersParams{Region: &region}).Return(&serverless.ListTrafficFiltersResponse{
+		JSON200: &serverless.TrafficFilterList{Items: []serverless.TrafficFilterInfo{
+			{Id: "office-id", Name: "office", Region: region},
+			{Id: "vpn-id", Name: "vpn", Region: region},
+		}},
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+	}, nil).Times(3)
+
+	now := time.Now()
+	cache := NewFilterListCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	id, diags := ResolveTrafficFilterName(ctx, mockClient, cache, "office", region)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, "office-id", id)
+	id, diags = ResolveTrafficFilterName(ctx, mockClient, cache, "vpn", region)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, "vpn-id", id)
+
+	// Changes of traffic filters invalidate the listing of their region.
+	cache.Invalidate(region)
+	_, diags = ResolveTrafficFilterName(ctx, mockClient, cache, "office", region)
+	require.False(t, diags.HasError(), diags)
+
+	// Listings older than the TTL are not reused.
+	now = now.Add(time.Minute)
+	_, diags = ResolveTrafficFilterName(ctx, mockClient, cache, "office", region)
+	require.False(t, diags.HasError(), diags)
+}
@@ -0,0 +1,168 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package timeouts provides the timeouts attribute of resources waiting on
+// the API, such as `timeouts = { delete = "10m" }`.
+package timeouts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	create = "create"
+	read   = "read"
+	update = "update"
+	remove = "delete"
+)
+
+// Opts selects the operations whose timeout can be configured.
+type Opts struct {
+	Create bool
+	Read   bool
+	Update bool
+	Delete bool
+}
+
+func (o Opts) operations() []string {
+	var operations []string
+	if o.Create {
+		operations = append(operations, create)
+	}
+	if o.Read {
+		operations = append(operations, read)
+	}
+	if o.Update {
+		operations = append(operations, update)
+	}
+	if o.Delete {
+		operations = append(operations, remove)
+	}
+	return operations
+}
+
+// Attributes returns the optional timeouts attribute with the selected operations.
+func Attributes(opts Opts) schema.SingleNestedAttribute {
+	attributes := make(map[string]schema.Attribute)
+	for _, operation := range opts.operations() {
+		attributes[operation] = schema.StringAttribute{
+			Description: fmt.Sprintf(`How long to wait for the %s operation, as a duration such as "30s" or "10m"`, operation),
+			Optional:    true,
+			Validators:  []validator.String{durationValidator{}},
+		}
+	}
+	return schema.SingleNestedAttribute{
+		Description: "Timeouts of the operations waiting on the API",
+		Optional:    true,
+		Attributes:  attributes,
+	}
+}
+
+// AttrTypes returns the attribute types of the timeouts attribute with the selected operations.
+func AttrTypes(opts Opts) map[string]attr.Type {
+	attrTypes := make(map[string]attr.Type)
+	for _, operation := range opts.operations() {
+		attrTypes[operation] = types.StringType
+	}
+	return attrTypes
+}
+
+// Create returns the configured create timeout, or def if it's not configured.
+func Create(value types.Object, def time.Duration) (time.Duration, diag.Diagnostics) {
+	return timeout(value, create, def)
+}
+
+// Read returns the configured read timeout, or def if it's not configured.
+func Read(value types.Object, def time.Duration) (time.Duration, diag.Diagnostics) {
+	return timeout(value, read, def)
+}
+
+// Update returns the configured update timeout, or def if it's not configured.
+func Update(value types.Object, def time.Duration) (time.Duration, diag.Diagnostics) {
+	return timeout(value, update, def)
+}
+
+// Delete returns the configured delete timeout, or def if it's not configured.
+func Delete(value types.Object, def time.Duration) (time.Duration, diag.Diagnostics) {
+	return timeout(value, remove, def)
+}
+
+func timeout(value types.Object, operation string, def time.Duration) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if value.IsNull() || value.IsUnknown() {
+		return def, diags
+	}
+
+	configured, ok := value.Attributes()[operation].(types.String)
+	if !ok || configured.IsNull() || configured.IsUnknown() {
+		return def, diags
+	}
+
+	d, err := time.ParseDuration(configured.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("timeouts").AtName(operation), "Invalid timeout", err.Error())
+		return def, diags
+	}
+	return d, diags
+}
+
+// Sleep waits for the given duration, returning early with the context's error
+// if it's done before.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return `Value must be a positive duration such as "30s" or "10m"`
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("%q is not a positive duration", req.ConfigValue.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid timeout", err.Error())
+	}
+}
@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package timeouts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelete(t *testing.T) {
+	attrTypes := AttrTypes(Opts{Delete: true})
+	tests := []struct {
+		name      string
+		value     types.Object
+		expected  time.Duration
+		expectErr bool
+	}{
+		{name: "default without timeouts", value: types.ObjectNull(attrTypes), expected: 5 * time.Minute},
+		{
+			name:     "default without delete timeout",
+			value:    types.ObjectValueMust(attrTypes, map[string]attr.Value{"delete": types.StringNull()}),
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "configured delete timeout",
+			value:    types.ObjectValueMust(attrTypes, map[string]attr.Value{"delete": types.StringValue("10m")}),
+			expected: 10 * time.Minute,
+		},
+		{
+			name:      "invalid delete timeout",
+			value:     types.ObjectValueMust(attrTypes, map[string]attr.Value{"delete": types.StringValue("ten minutes")}),
+			expected:  5 * time.Minute,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout, diags := Delete(tt.value, 5*time.Minute)
+			require.Equal(t, tt.expectErr, diags.HasError(), diags)
+			require.Equal(t, tt.expected, timeout)
+		})
+	}
+}
+
+func TestSleep(t *testing.T) {
+	require.NoError(t, Sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, Sleep(ctx, time.Hour), context.Canceled)
+}
@@ -0,0 +1,189 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are never written to the body log.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// sensitiveFields are JSON object keys whose values are never written to the body log.
+var sensitiveFields = []string{"password", "secret", "token", "api_key", "apikey", "credentials"}
+
+type bodyLogTransport struct {
+	next http.RoundTripper
+	now  func() time.Time
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewBodyLogTransport returns a RoundTripper which writes every request and
+// response, including their full bodies, to w. Credentials are redacted from
+// the headers and from JSON bodies. A nil writer disables the logging.
+func NewBodyLogTransport(next http.RoundTripper, w io.Writer) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if w == nil {
+		return next
+	}
+
+	return &bodyLogTransport{next: next, now: time.Now, w: w}
+}
+
+func (t *bodyLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if hasBody(req) {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	start := t.now()
+	res, err := t.next.RoundTrip(req)
+	elapsed := t.now().Sub(start)
+
+	var entry strings.Builder
+	fmt.Fprintf(&entry, "==> %s %s %s\n", start.UTC().Format(time.RFC3339), req.Method, req.URL)
+	writeHeaders(&entry, req.Header)
+	writeBody(&entry, reqBody)
+
+	if err != nil {
+		fmt.Fprintf(&entry, "<== error after %s: %s\n\n", elapsed, err)
+		t.write(entry.String())
+		return res, err
+	}
+
+	resBody, readErr := readBody(&res.Body)
+	fmt.Fprintf(&entry, "<== %s (%s)\n", res.Status, elapsed)
+	writeHeaders(&entry, res.Header)
+	writeBody(&entry, resBody)
+	if readErr != nil {
+		fmt.Fprintf(&entry, "(failed reading the response body: %s)\n", readErr)
+	}
+	entry.WriteString("\n")
+	t.write(entry.String())
+
+	return res, readErr
+}
+
+func (t *bodyLogTransport) write(entry string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.w, entry)
+}
+
+// readBody reads the whole response body and replaces it by an in-memory
+// copy, so that it can still be consumed by the client.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+func writeHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			value = redacted
+		}
+		fmt.Fprintf(w, "%s: %s\n", name, value)
+	}
+}
+
+func writeBody(w io.Writer, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", redactBody(body))
+}
+
+// redactBody replaces the values of sensitive fields in JSON bodies. Other
+// bodies are returned unchanged.
+func redactBody(body []byte) []byte {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = redactValue(elem)
+		}
+	}
+	return value
+}
+
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range sensitiveFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLogTransport_WritesRequestAndResponse(t *testing.T) {
+	var received string
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		received = string(body)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Status:     "201 Created",
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"project-id","credentials":{"username":"admin","password":"hunter2"}}`)),
+		}, nil
+	})
+
+	var log bytes.Buffer
+	rt := NewBodyLogTransport(next, &log)
+
+	req := httptest.NewRequest(http.MethodPost, "https://cloud.elastic.co/api/v1/serverless/projects/elasticsearch", strings.NewReader(`{"name":"my-project"}`))
+	req.Header.Set("Authorization", "ApiKey secret")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	// Both the next transport and the client still get the full bodies.
+	require.Equal(t, `{"name":"my-project"}`, received)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "hunter2")
+
+	entry := log.String()
+	require.Contains(t, entry, "POST https://cloud.elastic.co/api/v1/serverless/projects/elasticsearch\n")
+	require.Contains(t, entry, "Authorization: [REDACTED]\n")
+	require.Contains(t, entry, `{"name":"my-project"}`)
+	require.Contains(t, entry, "<== 201 Created")
+	require.Contains(t, entry, `{"credentials":"[REDACTED]","id":"project-id"}`)
+	require.NotContains(t, entry, "secret")
+	require.NotContains(t, entry, "hunter2")
+}
+
+func TestBodyLogTransport_WritesErrors(t *testing.T) {
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, io.ErrUnexpectedEOF
+	})
+
+	var log bytes.Buffer
+	_, err := NewBodyLogTransport(next, &log).RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil))
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Contains(t, log.String(), "GET https://cloud.elastic.co")
+	require.Contains(t, log.String(), "unexpected EOF")
+}
+
+func TestBodyLogTransport_DisabledWithoutWriter(t *testing.T) {
+	next := okTransport(new(int))
+	require.IsType(t, next, NewBodyLogTransport(next, nil))
+}
+
+func TestRedactBody(t *testing.T) {
+	require.Equal(t, `[{"api_key":"[REDACTED]","name":"x"}]`, string(redactBody([]byte(`[{"name":"x","api_key":"abc"}]`))))
+	require.Equal(t, "not json", string(redactBody([]byte("not json"))))
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitBreakerThreshold is the number of consecutive 503 responses opening the circuit.
+	DefaultCircuitBreakerThreshold = 5
+	// DefaultCircuitBreakerCooldown is the time requests fail fast for once the circuit is open.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned for requests which aren't sent because the API is overloaded.
+var ErrCircuitOpen = errors.New("the serverless API is overloaded")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreakerTransport struct {
+	next      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	state       circuitState
+	failures    int
+	openUntil   time.Time
+	trialActive bool
+}
+
+// NewCircuitBreakerTransport returns a RoundTripper which stops sending requests
+// once threshold consecutive requests failed with 503 Service Unavailable. While
+// the circuit is open, requests fail fast with ErrCircuitOpen for the cooldown,
+// or for as long as the last response asked for via Retry-After if that's longer.
+// A single trial request is then let through, closing the circuit if it succeeds
+// and opening it again otherwise.
+func NewCircuitBreakerTransport(next http.RoundTripper, threshold int, cooldown time.Duration) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if threshold < 1 {
+		threshold = DefaultCircuitBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitBreakerCooldown
+	}
+
+	return &circuitBreakerTransport{next: next, threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.allow(); err != nil {
+		if req.Body != nil {
+			_, _ = io.Copy(io.Discard, req.Body)
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	res, err := t.next.RoundTrip(req)
+	t.record(res, err)
+	return res, err
+}
+
+// allow reports whether a request may be sent, moving an open circuit to
+// half-open once the cooldown has passed.
+func (t *circuitBreakerTransport) allow() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch t.state {
+	case circuitOpen:
+		if wait := t.openUntil.Sub(t.now()); wait > 0 {
+			return fmt.Errorf("%w: %d consecutive requests failed with 503 Service Unavailable, requests are paused for another %s",
+				ErrCircuitOpen, t.failures, wait.Round(time.Second))
+		}
+		t.state = circuitHalfOpen
+		t.trialActive = true
+		return nil
+	case circuitHalfOpen:
+		if t.trialActive {
+			return fmt.Errorf("%w: waiting for a trial request to check whether the API has recovered", ErrCircuitOpen)
+		}
+		t.trialActive = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (t *circuitBreakerTransport) record(res *http.Response, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	wasTrial := t.state == circuitHalfOpen
+	t.trialActive = false
+
+	// Transport errors don't tell anything about the load of the API.
+	if err != nil || res == nil {
+		if wasTrial {
+			t.state = circuitOpen
+			t.openUntil = t.now().Add(t.cooldown)
+		}
+		return
+	}
+
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.state = circuitClosed
+		t.failures = 0
+		return
+	}
+
+	t.failures++
+	if wasTrial || t.failures >= t.threshold {
+		t.state = circuitOpen
+		t.openUntil = t.now().Add(t.cooldownFor(res))
+	}
+}
+
+func (t *circuitBreakerTransport) cooldownFor(res *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+		if wait := time.Duration(seconds) * time.Second; wait > t.cooldown {
+			return wait
+		}
+	}
+	return t.cooldown
+}
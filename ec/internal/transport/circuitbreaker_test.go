@@ -0,0 +1,154 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestCircuitBreaker(next http.RoundTripper, clock *fakeClock) *circuitBreakerTransport {
+	rt := NewCircuitBreakerTransport(next, 3, time.Minute).(*circuitBreakerTransport)
+	rt.now = clock.Now
+	return rt
+}
+
+func switchableStatusTransport(calls *int, statusCode *int, header http.Header) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*calls++
+		return &http.Response{StatusCode: *statusCode, Header: header, Body: http.NoBody}, nil
+	}
+}
+
+func send(rt http.RoundTripper) (*http.Response, error) {
+	return rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil))
+}
+
+func TestCircuitBreakerTransport_OpensAfterConsecutiveUnavailable(t *testing.T) {
+	var calls int
+	status := http.StatusServiceUnavailable
+	clock := &fakeClock{now: time.Now()}
+	rt := newTestCircuitBreaker(switchableStatusTransport(&calls, &status, http.Header{}), clock)
+
+	for i := 0; i < 3; i++ {
+		res, err := send(rt)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	}
+	require.Equal(t, circuitOpen, rt.state)
+
+	_, err := send(rt)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Contains(t, err.Error(), "3 consecutive requests failed with 503 Service Unavailable, requests are paused for another 1m0s")
+	require.Equal(t, 3, calls)
+}
+
+func TestCircuitBreakerTransport_SuccessResetsFailures(t *testing.T) {
+	var calls int
+	status := http.StatusServiceUnavailable
+	rt := newTestCircuitBreaker(switchableStatusTransport(&calls, &status, http.Header{}), &fakeClock{now: time.Now()})
+
+	for _, s := range []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK, http.StatusServiceUnavailable, http.StatusServiceUnavailable} {
+		status = s
+		_, err := send(rt)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, circuitClosed, rt.state)
+	require.Equal(t, 2, rt.failures)
+}
+
+func TestCircuitBreakerTransport_HalfOpenRecovers(t *testing.T) {
+	var calls int
+	status := http.StatusServiceUnavailable
+	clock := &fakeClock{now: time.Now()}
+	rt := newTestCircuitBreaker(switchableStatusTransport(&calls, &status, http.Header{}), clock)
+
+	for i := 0; i < 3; i++ {
+		_, _ = send(rt)
+	}
+	require.Equal(t, circuitOpen, rt.state)
+
+	// The first request after the cooldown is the trial, concurrent ones still fail fast.
+	clock.now = clock.now.Add(time.Minute)
+	require.NoError(t, rt.allow())
+	require.Equal(t, circuitHalfOpen, rt.state)
+	require.ErrorIs(t, rt.allow(), ErrCircuitOpen)
+
+	status = http.StatusOK
+	rt.record(&http.Response{StatusCode: status}, nil)
+	require.Equal(t, circuitClosed, rt.state)
+
+	res, err := send(rt)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, 4, calls)
+}
+
+func TestCircuitBreakerTransport_HalfOpenReopensOnFailure(t *testing.T) {
+	var calls int
+	status := http.StatusServiceUnavailable
+	clock := &fakeClock{now: time.Now()}
+	rt := newTestCircuitBreaker(switchableStatusTransport(&calls, &status, http.Header{}), clock)
+
+	for i := 0; i < 3; i++ {
+		_, _ = send(rt)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	res, err := send(rt)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	require.Equal(t, circuitOpen, rt.state)
+
+	_, err = send(rt)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, 4, calls)
+}
+
+func TestCircuitBreakerTransport_HonoursLongerRetryAfter(t *testing.T) {
+	var calls int
+	status := http.StatusServiceUnavailable
+	clock := &fakeClock{now: time.Now()}
+	rt := newTestCircuitBreaker(switchableStatusTransport(&calls, &status, http.Header{"Retry-After": []string{"120"}}), clock)
+
+	for i := 0; i < 3; i++ {
+		_, _ = send(rt)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	_, err := send(rt)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	clock.now = clock.now.Add(time.Minute)
+	_, err = send(rt)
+	require.NoError(t, err)
+	require.Equal(t, 4, calls)
+}
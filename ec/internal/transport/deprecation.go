@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type deprecationsKey struct{}
+
+// Deprecations collects the deprecation notices the API returned for the
+// requests made with a context created by CollectDeprecations.
+type Deprecations struct {
+	mu      sync.Mutex
+	notices []string
+}
+
+// CollectDeprecations returns a context which records the deprecation notices
+// of all API requests made with it in the returned Deprecations.
+func CollectDeprecations(ctx context.Context) (context.Context, *Deprecations) {
+	d := &Deprecations{}
+	return context.WithValue(ctx, deprecationsKey{}, d), d
+}
+
+func (d *Deprecations) add(notice string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, n := range d.notices {
+		if n == notice {
+			return
+		}
+	}
+	d.notices = append(d.notices, notice)
+}
+
+// Diagnostics returns a warning for every collected deprecation notice.
+func (d *Deprecations) Diagnostics() diag.Diagnostics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var diags diag.Diagnostics
+	for _, notice := range d.notices {
+		diags.AddWarning("Deprecated Serverless API endpoint", notice)
+	}
+	return diags
+}
+
+type deprecationTransport struct {
+	next http.RoundTripper
+}
+
+// NewDeprecationTransport returns a RoundTripper which logs the deprecation
+// notices sent by the API in the Warning and Sunset response headers, and
+// records them in the Deprecations of the request context if there are any.
+func NewDeprecationTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &deprecationTransport{next: next}
+}
+
+func (t *deprecationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res == nil {
+		return res, err
+	}
+
+	if notice := deprecationNotice(req, res); notice != "" {
+		ctx := req.Context()
+		tflog.Warn(ctx, notice)
+		if d, ok := ctx.Value(deprecationsKey{}).(*Deprecations); ok {
+			d.add(notice)
+		}
+	}
+
+	return res, err
+}
+
+func deprecationNotice(req *http.Request, res *http.Response) string {
+	warnings := res.Header.Values("Warning")
+	sunset := res.Header.Get("Sunset")
+	if len(warnings) == 0 && sunset == "" {
+		return ""
+	}
+
+	var notice strings.Builder
+	fmt.Fprintf(&notice, "The Serverless API endpoint %s %s is deprecated", req.Method, req.URL.Path)
+	if sunset != "" {
+		fmt.Fprintf(&notice, " and will be removed after %s", sunset)
+	}
+	notice.WriteString(".")
+	for _, warning := range warnings {
+		fmt.Fprintf(&notice, "\n%s", warning)
+	}
+	notice.WriteString("\nPlease upgrade the provider before the endpoint is removed.")
+	return notice.String()
+}
@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func headerResponseTransport(header http.Header) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody}, nil
+	}
+}
+
+func TestDeprecationTransport_CollectsSunset(t *testing.T) {
+	rt := NewDeprecationTransport(headerResponseTransport(http.Header{
+		"Sunset":  []string{"Sat, 31 Oct 2026 23:59:59 GMT"},
+		"Warning": []string{`299 - "Use /api/v2/serverless/traffic-filters instead"`},
+	}))
+
+	ctx, deprecations := CollectDeprecations(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co/api/v1/serverless/traffic-filters", nil).WithContext(ctx)
+
+	// Repeated calls to the same endpoint are only reported once.
+	for i := 0; i < 2; i++ {
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	diags := deprecations.Diagnostics()
+	require.Len(t, diags, 1)
+	require.Equal(t, "Deprecated Serverless API endpoint", diags[0].Summary())
+	require.Equal(t, "The Serverless API endpoint GET /api/v1/serverless/traffic-filters is deprecated and will be removed after Sat, 31 Oct 2026 23:59:59 GMT.\n"+
+		`299 - "Use /api/v2/serverless/traffic-filters instead"`+"\n"+
+		"Please upgrade the provider before the endpoint is removed.", diags[0].Detail())
+}
+
+func TestDeprecationTransport_IgnoresRegularResponses(t *testing.T) {
+	rt := NewDeprecationTransport(headerResponseTransport(http.Header{}))
+
+	ctx, deprecations := CollectDeprecations(context.Background())
+	_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil).WithContext(ctx))
+
+	require.NoError(t, err)
+	require.Empty(t, deprecations.Diagnostics())
+}
+
+func TestDeprecationTransport_WithoutCollector(t *testing.T) {
+	rt := NewDeprecationTransport(headerResponseTransport(http.Header{
+		"Sunset": []string{"Sat, 31 Oct 2026 23:59:59 GMT"},
+	}))
+
+	res, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,155 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// requestIDHeader is the response header identifying a request in the API logs.
+const requestIDHeader = "X-Request-Id"
+
+type failuresKey struct{}
+
+// Failures records the last failed API request made with a context created by
+// CollectFailures, so that diagnostics can refer to it.
+type Failures struct {
+	mu        sync.Mutex
+	requestID string
+	errorCode string
+}
+
+// CollectFailures returns a context which records the failed API requests made
+// with it in the returned Failures.
+func CollectFailures(ctx context.Context) (context.Context, *Failures) {
+	f := &Failures{}
+	return context.WithValue(ctx, failuresKey{}, f), f
+}
+
+func (f *Failures) set(requestID, errorCode string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requestID, f.errorCode = requestID, errorCode
+}
+
+// Last returns the request ID and the API error code of the last failed
+// request, empty if there is none or the API didn't report them.
+func (f *Failures) Last() (requestID, errorCode string) {
+	if f == nil {
+		return "", ""
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requestID, f.errorCode
+}
+
+type failureTransport struct {
+	next http.RoundTripper
+}
+
+// NewFailureTransport returns a RoundTripper which records the request ID and
+// the error code of failed responses in the Failures of the request context.
+func NewFailureTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &failureTransport{next: next}
+}
+
+func (t *failureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res == nil || res.StatusCode < http.StatusBadRequest {
+		return res, err
+	}
+
+	f, ok := req.Context().Value(failuresKey{}).(*Failures)
+	if !ok {
+		return res, err
+	}
+
+	var body []byte
+	if res.Body != nil {
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	f.set(res.Header.Get(requestIDHeader), errorCode(body))
+	return res, nil
+}
+
+// errorCode returns the code of the first error of an API error response.
+func errorCode(body []byte) string {
+	var resp struct {
+		Code   string `json:"code"`
+		Errors []struct {
+			Code string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return ""
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors[0].Code
+	}
+	return resp.Code
+}
+
+type diagnosticLine struct {
+	Summary       string `json:"summary"`
+	Detail        string `json:"detail"`
+	Severity      string `json:"severity"`
+	AttributePath string `json:"attribute_path,omitempty"`
+	RequestID     string `json:"request_id,omitempty"`
+	ErrorCode     string `json:"error_code,omitempty"`
+}
+
+// LogDiagnosticsJSON logs every diagnostic as a single line JSON object, for
+// tools ingesting the logs. Errors refer to the last failed API request.
+func LogDiagnosticsJSON(ctx context.Context, diags diag.Diagnostics, failures *Failures) {
+	requestID, code := failures.Last()
+	for _, d := range diags {
+		line := diagnosticLine{
+			Summary:  d.Summary(),
+			Detail:   d.Detail(),
+			Severity: d.Severity().String(),
+		}
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			line.AttributePath = withPath.Path().String()
+		}
+		if d.Severity() == diag.SeverityError {
+			line.RequestID, line.ErrorCode = requestID, code
+		}
+
+		encoded, err := json.Marshal(line)
+		if err != nil {
+			continue
+		}
+		tflog.Info(ctx, string(encoded))
+	}
+}
@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func failedResponseTransport(statusCode int, header http.Header, body string) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: statusCode, Header: header, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+}
+
+func TestFailureTransport_RecordsFailedRequests(t *testing.T) {
+	rt := NewFailureTransport(failedResponseTransport(http.StatusBadRequest,
+		http.Header{"X-Request-Id": []string{"req-1"}},
+		`{"errors":[{"code":"traffic_filter.invalid_source","message":"invalid source"}]}`,
+	))
+
+	ctx, failures := CollectFailures(context.Background())
+	res, err := rt.RoundTrip(httptest.NewRequest(http.MethodPost, "https://cloud.elastic.co", nil).WithContext(ctx))
+	require.NoError(t, err)
+
+	requestID, code := failures.Last()
+	require.Equal(t, "req-1", requestID)
+	require.Equal(t, "traffic_filter.invalid_source", code)
+
+	// The body can still be read by the client.
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "invalid source")
+}
+
+func TestFailureTransport_IgnoresSuccessfulRequests(t *testing.T) {
+	rt := NewFailureTransport(failedResponseTransport(http.StatusOK, http.Header{"X-Request-Id": []string{"req-1"}}, `{}`))
+
+	ctx, failures := CollectFailures(context.Background())
+	_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil).WithContext(ctx))
+	require.NoError(t, err)
+
+	requestID, code := failures.Last()
+	require.Empty(t, requestID)
+	require.Empty(t, code)
+}
+
+func TestLogDiagnosticsJSON(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	failures := &Failures{}
+	failures.set("req-1", "not_found")
+
+	var diags diag.Diagnostics
+	diags.AddAttributeWarning(path.Root("name"), "Name changed", "The name was changed.")
+	diags.AddError("API Read Failed", "Failed to read traffic filter.")
+	LogDiagnosticsJSON(ctx, diags, failures)
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.JSONEq(t, `{"summary":"Name changed","detail":"The name was changed.","severity":"Warning","attribute_path":"name"}`, entries[0]["@message"].(string))
+	require.JSONEq(t, `{"summary":"API Read Failed","detail":"Failed to read traffic filter.","severity":"Error","request_id":"req-1","error_code":"not_found"}`, entries[1]["@message"].(string))
+}
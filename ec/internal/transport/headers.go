@@ -0,0 +1,81 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// HeaderNameRegex matches a valid HTTP header field name (RFC 7230 token).
+var HeaderNameRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+// reservedHeaders are set by the client itself, authenticating the requests, describing their
+// bodies and guarding concurrent modifications. Extra headers must not override them.
+var reservedHeaders = map[string]bool{
+	"Authorization":     true,
+	"Content-Length":    true,
+	"Content-Type":      true,
+	"Host":              true,
+	"If-Match":          true,
+	"If-None-Match":     true,
+	"Transfer-Encoding": true,
+}
+
+// IsReservedHeader reports whether the header with the given name is set by the client itself,
+// and thus can't be set as an extra header.
+func IsReservedHeader(name string) bool {
+	return reservedHeaders[http.CanonicalHeaderKey(name)]
+}
+
+type headerTransport struct {
+	next    http.RoundTripper
+	headers http.Header
+}
+
+// NewHeaderTransport returns a RoundTripper which sets the given headers on
+// every outgoing request before passing it on to next. Reserved headers are
+// left untouched, see IsReservedHeader.
+func NewHeaderTransport(next http.RoundTripper, headers map[string]string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if len(headers) == 0 {
+		return next
+	}
+
+	h := make(http.Header, len(headers))
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+
+	return &headerTransport{next: next, headers: h}
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the given request.
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		if reservedHeaders[name] {
+			continue
+		}
+		req.Header[name] = values
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,104 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewHeaderTransport(t *testing.T) {
+	t.Run("returns next when there are no headers", func(t *testing.T) {
+		next := roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+		require.NotNil(t, NewHeaderTransport(next, nil))
+		_, ok := NewHeaderTransport(next, nil).(*headerTransport)
+		require.False(t, ok)
+	})
+
+	t.Run("sets the headers on the outgoing request without modifying the original", func(t *testing.T) {
+		var sent *http.Request
+		next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		})
+
+		rt := NewHeaderTransport(next, map[string]string{
+			"X-Request-Source": "terraform",
+			"x-team":           "platform",
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co/api/v1/serverless/traffic-filters", nil)
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		require.Equal(t, "terraform", sent.Header.Get("X-Request-Source"))
+		require.Equal(t, "platform", sent.Header.Get("X-Team"))
+		require.Empty(t, req.Header.Get("X-Request-Source"))
+	})
+
+	t.Run("leaves reserved headers untouched", func(t *testing.T) {
+		var sent *http.Request
+		next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		})
+
+		rt := NewHeaderTransport(next, map[string]string{
+			"authorization": "Bearer other",
+			"If-Match":      "*",
+			"X-Team":        "platform",
+		})
+
+		req := httptest.NewRequest(http.MethodPatch, "https://cloud.elastic.co/api/v1/serverless/projects/security/id", nil)
+		req.Header.Set("Authorization", "ApiKey secret")
+		req.Header.Set("If-Match", `"etag"`)
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		require.Equal(t, "ApiKey secret", sent.Header.Get("Authorization"))
+		require.Equal(t, `"etag"`, sent.Header.Get("If-Match"))
+		require.Equal(t, "platform", sent.Header.Get("X-Team"))
+	})
+}
+
+func TestIsReservedHeader(t *testing.T) {
+	for _, name := range []string{"Authorization", "authorization", "Host", "Content-Type", "if-match"} {
+		require.True(t, IsReservedHeader(name), name)
+	}
+	for _, name := range []string{"X-Request-Source", "Accept"} {
+		require.False(t, IsReservedHeader(name), name)
+	}
+}
+
+func TestHeaderNameRegex(t *testing.T) {
+	for _, name := range []string{"X-Request-Source", "x_custom", "Accept"} {
+		require.True(t, HeaderNameRegex.MatchString(name), name)
+	}
+	for _, name := range []string{"", "X Request", "X-Request:", "X-Ünicode"} {
+		require.False(t, HeaderNameRegex.MatchString(name), name)
+	}
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"math"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// NewRateLimitTransport returns a RoundTripper which throttles requests to qps
+// requests per second, allowing bursts of up to burst requests. Waiting for a
+// token is aborted when the request context is done. A non-positive qps
+// disables the rate limiting.
+func NewRateLimitTransport(next http.RoundTripper, qps float64, burst int) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if qps <= 0 {
+		return next
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimitTransport{next: next, limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+// BurstForQPS returns the burst allowed for the given steady-state rate, which
+// permits one second worth of requests at once, rounded up. The api_qps
+// provider attribute documents it, since the burst can't be set on its own.
+func BurstForQPS(qps float64) int {
+	return int(math.Max(1, math.Ceil(qps)))
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func okTransport(calls *int) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*calls++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}
+}
+
+func TestRateLimitTransport_Throttles(t *testing.T) {
+	var calls int
+	rt := NewRateLimitTransport(okTransport(&calls), 20, 1)
+
+	start := time.Now()
+	for i := 0; i < 11; i++ {
+		_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil))
+		require.NoError(t, err)
+	}
+	elapsed := time.Since(start)
+
+	// The first call is served from the initial token, the other 10 take 50ms each.
+	require.Equal(t, 11, calls)
+	require.GreaterOrEqual(t, elapsed, 450*time.Millisecond)
+	require.Less(t, elapsed, 2*time.Second)
+}
+
+func TestRateLimitTransport_RespectsContextCancellation(t *testing.T) {
+	var calls int
+	rt := NewRateLimitTransport(okTransport(&calls), 0.1, 1)
+
+	_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil).WithContext(ctx))
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+}
+
+func TestRateLimitTransport_DisabledWithoutQPS(t *testing.T) {
+	next := okTransport(new(int))
+	require.IsType(t, next, NewRateLimitTransport(next, 0, 0))
+}
+
+func TestBurstForQPS(t *testing.T) {
+	require.Equal(t, 1, BurstForQPS(0.5))
+	require.Equal(t, 1, BurstForQPS(1))
+	require.Equal(t, 3, BurstForQPS(2.5))
+	require.Equal(t, 10, BurstForQPS(10))
+}
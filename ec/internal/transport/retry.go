@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed serverless API request is retried.
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff is the backoff before the first retry, it's doubled with every further attempt.
+	DefaultRetryBackoff = time.Second
+	// DefaultRetryMaxBackoff caps the backoff between two attempts.
+	DefaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryConfig configures the retries of a retry transport.
+type RetryConfig struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+type retryTransport struct {
+	next http.RoundTripper
+	cfg  RetryConfig
+}
+
+// NewRetryTransport returns a RoundTripper which retries requests failing with a transient error.
+// The backoff between two attempts grows exponentially up to cfg.MaxBackoff. Retries stop early
+// if the next attempt would start after the deadline of the request context.
+func NewRetryTransport(next http.RoundTripper, cfg RetryConfig) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultRetryBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultRetryMaxBackoff
+	}
+
+	return &retryTransport{next: next, cfg: cfg}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && hasBody(req) {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		res, err := t.next.RoundTrip(req)
+		if attempt >= t.cfg.MaxRetries || !IsRetriable(req.Method, res, err) {
+			return res, err
+		}
+
+		// The body has already been consumed and can't be sent again.
+		if hasBody(req) && req.GetBody == nil {
+			return res, err
+		}
+
+		wait := t.backoff(attempt, res)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return res, err
+		}
+
+		if res != nil && res.Body != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
+}
+
+// backoff returns the time to wait before the next attempt, preferring the
+// delay requested by the server via the Retry-After header.
+func (t *retryTransport) backoff(attempt int, res *http.Response) time.Duration {
+	wait := t.cfg.MaxBackoff
+	if shift := uint(attempt); shift < 32 {
+		if exp := t.cfg.Backoff << shift; exp > 0 && exp < wait {
+			wait = exp
+		}
+	}
+
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if wait > t.cfg.MaxBackoff {
+		wait = t.cfg.MaxBackoff
+	}
+	return wait
+}
+
+// IsRetriable reports whether a request which failed with the given response or error may succeed when sent again.
+// Requests rejected due to rate limiting or unavailability are retriable for all methods, since the server didn't
+// process them. Gateway errors are only retriable for idempotent methods, as the server may have processed them.
+func IsRetriable(method string, res *http.Response, err error) bool {
+	if err != nil || res == nil {
+		return false
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet || method == http.MethodHead
+	default:
+		return false
+	}
+}
@@ -0,0 +1,142 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func statusTransport(attempts *int32, statuses ...int) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(attempts, 1)
+		status := statuses[len(statuses)-1]
+		if int(n) <= len(statuses) {
+			status = statuses[n-1]
+		}
+		return &http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody}, nil
+	}
+}
+
+func TestRetryTransport_RetriesTransientErrors(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	next := statusTransport(&attempts, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	rt := NewRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		return next(req)
+	}), RetryConfig{MaxRetries: 3, Backoff: time.Millisecond})
+
+	req := httptest.NewRequest(http.MethodPatch, "https://cloud.elastic.co/api/v1/serverless/traffic-filters/id", strings.NewReader(`{"name":"filter"}`))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(`{"name":"filter"}`)), nil }
+	res, err := rt.RoundTrip(req)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(3), attempts)
+	require.Equal(t, []string{`{"name":"filter"}`, `{"name":"filter"}`, `{"name":"filter"}`}, bodies)
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	rt := NewRetryTransport(statusTransport(&attempts, http.StatusServiceUnavailable), RetryConfig{MaxRetries: 2, Backoff: time.Millisecond})
+
+	res, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	require.Equal(t, int32(3), attempts)
+}
+
+func TestRetryTransport_StopsBeforeContextDeadline(t *testing.T) {
+	var attempts int32
+	rt := NewRetryTransport(statusTransport(&attempts, http.StatusServiceUnavailable), RetryConfig{
+		MaxRetries: 10,
+		Backoff:    40 * time.Millisecond,
+		MaxBackoff: time.Second,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	req := httptest.NewRequest(http.MethodGet, "https://cloud.elastic.co", nil).WithContext(ctx)
+	res, err := rt.RoundTrip(req)
+
+	// 40ms + 80ms fit into the deadline, the next backoff of 160ms doesn't.
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	require.Equal(t, int32(3), attempts)
+	require.True(t, time.Now().Before(deadline), "retries exceeded the context deadline")
+}
+
+func TestRetryTransport_DoesNotRetryNonIdempotentGatewayErrors(t *testing.T) {
+	var attempts int32
+	rt := NewRetryTransport(statusTransport(&attempts, http.StatusBadGateway, http.StatusOK), RetryConfig{MaxRetries: 3, Backoff: time.Millisecond})
+
+	res, err := rt.RoundTrip(httptest.NewRequest(http.MethodPost, "https://cloud.elastic.co", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadGateway, res.StatusCode)
+	require.Equal(t, int32(1), attempts)
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	rt := NewRetryTransport(nil, RetryConfig{Backoff: time.Second, MaxBackoff: 5 * time.Second}).(*retryTransport)
+
+	require.Equal(t, time.Second, rt.backoff(0, nil))
+	require.Equal(t, 2*time.Second, rt.backoff(1, nil))
+	require.Equal(t, 4*time.Second, rt.backoff(2, nil))
+	require.Equal(t, 5*time.Second, rt.backoff(3, nil))
+	require.Equal(t, 5*time.Second, rt.backoff(100, nil))
+
+	retryAfter := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	require.Equal(t, 3*time.Second, rt.backoff(0, retryAfter))
+
+	retryAfter.Header.Set("Retry-After", "60")
+	require.Equal(t, 5*time.Second, rt.backoff(0, retryAfter))
+}
+
+func TestIsRetriable(t *testing.T) {
+	tests := []struct {
+		method   string
+		status   int
+		expected bool
+	}{
+		{http.MethodPost, http.StatusTooManyRequests, true},
+		{http.MethodPatch, http.StatusServiceUnavailable, true},
+		{http.MethodGet, http.StatusBadGateway, true},
+		{http.MethodGet, http.StatusGatewayTimeout, true},
+		{http.MethodPatch, http.StatusGatewayTimeout, false},
+		{http.MethodGet, http.StatusBadRequest, false},
+		{http.MethodGet, http.StatusInternalServerError, false},
+		{http.MethodGet, http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, IsRetriable(tt.method, &http.Response{StatusCode: tt.status}, nil), "%s %d", tt.method, tt.status)
+	}
+}
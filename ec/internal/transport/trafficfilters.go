@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+var trafficFiltersField = []byte(`"traffic_filters"`)
+
+type trafficFiltersTransport struct {
+	next http.RoundTripper
+}
+
+// NewTrafficFiltersTransport returns a RoundTripper which rewrites the traffic
+// filters of projects returned as an object keyed by traffic filter ID into the
+// documented array of traffic filters, so that both shapes can be decoded.
+func NewTrafficFiltersTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &trafficFiltersTransport{next: next}
+}
+
+func (t *trafficFiltersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res == nil || res.Body == nil || res.Body == http.NoBody {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if normalized, ok := normalizeTrafficFilters(body); ok {
+		body = normalized
+		res.ContentLength = int64(len(body))
+		res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res, nil
+}
+
+// normalizeTrafficFilters rewrites keyed-object traffic filters in a project,
+// or in the items of a project list, into arrays. It reports whether the body
+// has been changed.
+func normalizeTrafficFilters(body []byte) ([]byte, bool) {
+	if !bytes.Contains(body, trafficFiltersField) {
+		return body, false
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return body, false
+	}
+
+	changed := normalizeProject(object)
+
+	var items []map[string]json.RawMessage
+	if raw, ok := object["items"]; ok && json.Unmarshal(raw, &items) == nil {
+		itemsChanged := false
+		for _, item := range items {
+			if normalizeProject(item) {
+				itemsChanged = true
+			}
+		}
+		if itemsChanged {
+			if raw, err := json.Marshal(items); err == nil {
+				object["items"] = raw
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return body, false
+	}
+	normalized, err := json.Marshal(object)
+	if err != nil {
+		return body, false
+	}
+	return normalized, true
+}
+
+func normalizeProject(project map[string]json.RawMessage) bool {
+	raw, ok := project["traffic_filters"]
+	if !ok {
+		return false
+	}
+
+	var keyed map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &keyed); err != nil || keyed == nil {
+		// Already an array, or null.
+		return false
+	}
+
+	ids := make([]string, 0, len(keyed))
+	for id := range keyed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	filters := make([]map[string]json.RawMessage, 0, len(keyed))
+	for _, id := range ids {
+		filter := map[string]json.RawMessage{}
+		// The values may be traffic filter objects, or just a placeholder like true.
+		_ = json.Unmarshal(keyed[id], &filter)
+		filter["id"], _ = json.Marshal(id)
+		filters = append(filters, filter)
+	}
+
+	normalized, err := json.Marshal(filters)
+	if err != nil {
+		return false
+	}
+	project["traffic_filters"] = normalized
+	return true
+}
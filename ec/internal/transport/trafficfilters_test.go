@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transport
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+)
+
+func trafficFiltersResponse(t *testing.T, body string) []byte {
+	t.Helper()
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Length": []string{"999"}},
+			ContentLength: 999,
+			Body:          io.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+
+	res, err := NewTrafficFiltersTransport(next).RoundTrip(httptest.NewRequest(http.MethodGet, "/api/v1/serverless/projects/elasticsearch/abc", nil))
+	require.NoError(t, err)
+	got, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	if string(got) != body {
+		require.Equal(t, int64(len(got)), res.ContentLength)
+		require.Equal(t, res.Header.Get("Content-Length"), strconv.Itoa(len(got)))
+	}
+	return got
+}
+
+func TestTrafficFiltersTransport_DecodesBothShapes(t *testing.T) {
+	var fromArray, fromObject serverless.ElasticsearchProject
+	require.NoError(t, json.Unmarshal(trafficFiltersResponse(t, `{"id":"abc","traffic_filters":[{"id":"a"},{"id":"b"}]}`), &fromArray))
+	require.NoError(t, json.Unmarshal(trafficFiltersResponse(t, `{"id":"abc","traffic_filters":{"b":{},"a":true}}`), &fromObject))
+
+	require.NotNil(t, fromObject.TrafficFilters)
+	require.Equal(t, serverless.TrafficFilters{{Id: "a"}, {Id: "b"}}, *fromObject.TrafficFilters)
+	require.Equal(t, fromArray, fromObject)
+}
+
+func TestTrafficFiltersTransport_NormalizesListItems(t *testing.T) {
+	var list struct {
+		Items []serverless.ElasticsearchProject `json:"items"`
+	}
+	body := `{"items":[{"id":"abc","traffic_filters":{"a":{"id":"a"}}},{"id":"def","traffic_filters":[{"id":"b"}]}]}`
+	require.NoError(t, json.Unmarshal(trafficFiltersResponse(t, body), &list))
+
+	require.Len(t, list.Items, 2)
+	require.Equal(t, serverless.TrafficFilters{{Id: "a"}}, *list.Items[0].TrafficFilters)
+	require.Equal(t, serverless.TrafficFilters{{Id: "b"}}, *list.Items[1].TrafficFilters)
+}
+
+func TestTrafficFiltersTransport_LeavesOtherBodiesUnchanged(t *testing.T) {
+	for _, body := range []string{
+		`{"id":"abc","name":"project"}`,
+		`{"id":"abc","traffic_filters":[{"id":"a"}]}`,
+		`{"id":"abc","traffic_filters":null}`,
+		`not json "traffic_filters"`,
+	} {
+		require.Equal(t, body, string(trafficFiltersResponse(t, body)))
+	}
+}
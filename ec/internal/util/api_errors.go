@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/elastic/terraform-provider-ec/ec/internal/transport"
+)
+
+// Summaries of diagnostics reporting failed API operations. They don't vary with
+// the resource, so that failures are easy to find in logs; the detail built by
+// OperationDetail tells which operation failed on which resource.
+const (
+	APICreateFailed = "API Create Failed"
+	APIReadFailed   = "API Read Failed"
+	APIUpdateFailed = "API Update Failed"
+	APIDeleteFailed = "API Delete Failed"
+	APIListFailed   = "API List Failed"
+)
+
+// OperationDetail prefixes the detail of a failed API operation with the
+// operation, e.g. "create traffic filter".
+func OperationDetail(operation, detail string) string {
+	return fmt.Sprintf("Failed to %s.\n\n%s", operation, detail)
+}
+
+// IsRetriableError returns true for errors of requests which couldn't be
+// completed and are likely transient, like timeouts and reset connections.
+// Other errors, e.g. cancelled operations or invalid endpoints, aren't.
+func IsRetriableError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, transport.ErrCircuitOpen) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// APIFailureDetail returns the diagnostic detail of an API request which
+// failed with the given response, with a hint whether retrying might help.
+// The hint agrees with the retries of the API client, see transport.IsRetriable.
+func APIFailureDetail(res *http.Response, body []byte) string {
+	var (
+		statusCode int
+		status     string
+		method     string
+	)
+	if res != nil {
+		statusCode, status = res.StatusCode, res.Status
+		if res.Request != nil {
+			method = res.Request.Method
+		}
+	}
+
+	return fmt.Sprintf("The API request failed with: %d %s\n%s\n\n%s",
+		statusCode,
+		status,
+		responseBodyDetail(body),
+		retriableHint(transport.IsRetriable(method, res, nil)))
+}
+
+// htmlSnippetLength is the maximum length of the snippet of HTML error pages kept in diagnostics.
+const htmlSnippetLength = 200
+
+// responseBodyDetail returns the body of a failed API response. HTML pages, typically returned
+// by a proxy or gateway in front of the API, are summarized instead of being dumped as is.
+func responseBodyDetail(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if !bytes.HasPrefix(trimmed, []byte("<")) || json.Valid(trimmed) {
+		return string(body)
+	}
+
+	snippet := strings.Join(strings.Fields(string(trimmed)), " ")
+	if runes := []rune(snippet); len(runes) > htmlSnippetLength {
+		snippet = string(runes[:htmlSnippetLength]) + "..."
+	}
+	return fmt.Sprintf("An HTML page was received instead of an API error, likely from a proxy or gateway in front of the API. It starts with:\n%s", snippet)
+}
+
+// RequestErrorDetail returns the diagnostic detail of an API request which
+// couldn't be completed, with a hint whether retrying might help.
+func RequestErrorDetail(err error) string {
+	return fmt.Sprintf("%s\n\n%s", err, retriableHint(IsRetriableError(err)))
+}
+
+func retriableHint(retriable bool) string {
+	if retriable {
+		return "retriable: true (the failure is likely transient, re-running the apply may succeed)"
+	}
+	return "retriable: false (re-running the apply without changes is unlikely to succeed)"
+}
@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func apiResponse(method string, statusCode int) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Request:    httptest.NewRequest(method, "https://cloud.elastic.co", nil),
+	}
+}
+
+func TestAPIFailureDetail(t *testing.T) {
+	require.Equal(t,
+		"The API request failed with: 429 429 Too Many Requests\n{\"error\":\"slow down\"}\n\nretriable: true (the failure is likely transient, re-running the apply may succeed)",
+		APIFailureDetail(apiResponse(http.MethodPost, http.StatusTooManyRequests), []byte(`{"error":"slow down"}`)),
+	)
+	require.Equal(t,
+		"The API request failed with: 400 400 Bad Request\n{\"error\":\"invalid name\"}\n\nretriable: false (re-running the apply without changes is unlikely to succeed)",
+		APIFailureDetail(apiResponse(http.MethodPost, http.StatusBadRequest), []byte(`{"error":"invalid name"}`)),
+	)
+}
+
+func TestAPIFailureDetail_Retriable(t *testing.T) {
+	tests := []struct {
+		method     string
+		statusCode int
+		expected   bool
+	}{
+		{method: http.MethodPost, statusCode: http.StatusTooManyRequests, expected: true},
+		{method: http.MethodPost, statusCode: http.StatusServiceUnavailable, expected: true},
+		{method: http.MethodGet, statusCode: http.StatusBadGateway, expected: true},
+		{method: http.MethodPost, statusCode: http.StatusBadGateway, expected: false},
+		{method: http.MethodPatch, statusCode: http.StatusGatewayTimeout, expected: false},
+		{method: http.MethodGet, statusCode: http.StatusInternalServerError, expected: false},
+		{method: http.MethodGet, statusCode: http.StatusNotFound, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.method, tt.statusCode), func(t *testing.T) {
+			detail := APIFailureDetail(apiResponse(tt.method, tt.statusCode), nil)
+			require.Contains(t, detail, fmt.Sprintf("retriable: %t", tt.expected))
+		})
+	}
+}
+
+func TestRequestErrorDetail(t *testing.T) {
+	require.Contains(t, RequestErrorDetail(fmt.Errorf("read: %w", syscall.ECONNRESET)), "retriable: true")
+	require.Contains(t, RequestErrorDetail(fmt.Errorf("request timed out: %w", context.DeadlineExceeded)), "retriable: true")
+	require.Contains(t, RequestErrorDetail(fmt.Errorf("request aborted: %w", context.Canceled)), "retriable: false")
+	require.Contains(t, RequestErrorDetail(errors.New("unsupported protocol scheme")), "retriable: false")
+}
+
+func TestOperationDetail(t *testing.T) {
+	require.Equal(t,
+		"Failed to read traffic filter.\n\nconnection reset by peer",
+		OperationDetail("read traffic filter", "connection reset by peer"),
+	)
+}
+
+func TestAPIFailureDetail_HTMLBody(t *testing.T) {
+	body := "<!DOCTYPE html>\n<html>\n  <head><title>502 Bad Gateway</title></head>\n  <body>\n    <center><h1>502 Bad Gateway</h1></center>\n" +
+		strings.Repeat("    <!-- padding -->\n", 20) + "  </body>\n</html>\n"
+
+	detail := APIFailureDetail(apiResponse(http.MethodGet, http.StatusBadGateway), []byte(body))
+	require.Equal(t,
+		"The API request failed with: 502 502 Bad Gateway\n"+
+			"An HTML page was received instead of an API error, likely from a proxy or gateway in front of the API. It starts with:\n"+
+			"<!DOCTYPE html> <html> <head><title>502 Bad Gateway</title></head> <body> <center><h1>502 Bad Gateway</h1></center> "+
+			"<!-- padding --> <!-- padding --> <!-- padding --> <!-- padding --> <!-- padding -->...\n\n"+
+			"retriable: true (the failure is likely transient, re-running the apply may succeed)",
+		detail,
+	)
+	require.NotContains(t, detail, "</html>")
+}
@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// MutationWindow is a daily time range in UTC, outside of which resources
+// refuse to create, update or delete objects. A nil window allows all changes.
+type MutationWindow struct {
+	// start and end are offsets from midnight. The window spans midnight if end is before start.
+	start, end time.Duration
+}
+
+// ParseMutationWindow parses a time range such as "22:00-06:00", in UTC.
+func ParseMutationWindow(window string) (*MutationWindow, error) {
+	from, to, found := strings.Cut(strings.TrimSpace(window), "-")
+	if !found {
+		return nil, fmt.Errorf("expected a time range such as 22:00-06:00, got %q", window)
+	}
+
+	start, err := parseTimeOfDay(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTimeOfDay(to)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return nil, fmt.Errorf("the time range %q is empty", window)
+	}
+	return &MutationWindow{start: start, end: end}, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day such as 06:00", strings.TrimSpace(s))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether changes are allowed at the given time.
+func (w *MutationWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+
+	t = t.UTC()
+	offset := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+func (w *MutationWindow) String() string {
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return format(w.start) + "-" + format(w.end)
+}
+
+// MutationWindowErrors returns an error if the operation isn't allowed at the given time.
+func MutationWindowErrors(w *MutationWindow, now time.Time, operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !w.Contains(now) {
+		diags.AddError(
+			"Outside of the allowed mutation window",
+			fmt.Sprintf("Refusing to %s at %s UTC, changes are only allowed during %s UTC as set by the allowed_mutation_window provider attribute. Apply the changes during the window.",
+				operation, now.UTC().Format("15:04"), w),
+		)
+	}
+	return diags
+}
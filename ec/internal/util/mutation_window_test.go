@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMutationWindow(t *testing.T) {
+	window, err := ParseMutationWindow(" 22:00 - 06:30 ")
+	require.NoError(t, err)
+	require.Equal(t, "22:00-06:30", window.String())
+
+	for _, invalid := range []string{"22:00", "22:00-25:00", "monday", "08:00-08:00"} {
+		_, err := ParseMutationWindow(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestMutationWindow_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	daytime, err := ParseMutationWindow("09:00-17:00")
+	require.NoError(t, err)
+	require.True(t, daytime.Contains(at(9, 0)))
+	require.True(t, daytime.Contains(at(16, 59)))
+	require.False(t, daytime.Contains(at(17, 0)))
+	require.False(t, daytime.Contains(at(8, 59)))
+	// Times are compared in UTC.
+	require.True(t, daytime.Contains(at(10, 0).In(time.FixedZone("UTC-8", -8*60*60))))
+
+	overnight, err := ParseMutationWindow("22:00-06:00")
+	require.NoError(t, err)
+	require.True(t, overnight.Contains(at(23, 0)))
+	require.True(t, overnight.Contains(at(5, 59)))
+	require.False(t, overnight.Contains(at(12, 0)))
+
+	var none *MutationWindow
+	require.True(t, none.Contains(at(12, 0)))
+}
+
+func TestMutationWindowErrors(t *testing.T) {
+	window, err := ParseMutationWindow("09:00-17:00")
+	require.NoError(t, err)
+
+	require.False(t, MutationWindowErrors(window, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), "create traffic filter").HasError())
+
+	diags := MutationWindowErrors(window, time.Date(2024, 3, 1, 20, 15, 0, 0, time.UTC), "create traffic filter")
+	require.True(t, diags.HasError())
+	require.Equal(t, "Outside of the allowed mutation window", diags[0].Summary())
+	require.Contains(t, diags[0].Detail(), "Refusing to create traffic filter at 20:15 UTC, changes are only allowed during 09:00-17:00 UTC")
+}
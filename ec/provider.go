@@ -20,7 +20,10 @@ package ec
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/elastic/cloud-sdk-go/pkg/api"
@@ -28,24 +31,44 @@ import (
 	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/deploymentsdatasource"
 	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/deploymenttemplates"
 	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/privatelinkdatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlessfilterprojectcompatibilitydatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlessprojectinfodatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlessprojectsmissingfilterdatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlessprojecttrafficfiltersdatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlesstrafficfilterbynamedatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlesstrafficfilterdatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlesstrafficfilterinventorydatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/serverlesstrafficfiltersbyiddatasource"
 	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/stackdatasource"
 	"github.com/elastic/terraform-provider-ec/ec/ecdatasource/trafficfilterdatasource"
+	"github.com/elastic/terraform-provider-ec/ec/ecfunction/associationidfunction"
+	"github.com/elastic/terraform-provider-ec/ec/ecfunction/filteriddifffunction"
+	"github.com/elastic/terraform-provider-ec/ec/ecfunction/mergerulesfunction"
+	"github.com/elastic/terraform-provider-ec/ec/ecfunction/rulestocsvfunction"
+	"github.com/elastic/terraform-provider-ec/ec/ecfunction/validatetrafficfilterfunction"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/deploymentresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/elasticsearchkeystoreresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/extensionresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/organizationresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/projectresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterassocresource"
+	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterdefaultsresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/serverlesstrafficfilterresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/snapshotrepositoryresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/trafficfilterassocresource"
 	"github.com/elastic/terraform-provider-ec/ec/ecresource/trafficfilterresource"
 	"github.com/elastic/terraform-provider-ec/ec/internal"
 	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/serverlessutil"
+	"github.com/elastic/terraform-provider-ec/ec/internal/transport"
 	"github.com/elastic/terraform-provider-ec/ec/internal/util"
 	"github.com/elastic/terraform-provider-ec/ec/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -58,11 +81,22 @@ const (
 	eceOnlyText      = "Available only when targeting ECE Installations or Elasticsearch Service Private"
 	saasRequiredText = "The only valid authentication mechanism for the Elasticsearch Service"
 
-	endpointDesc     = "Endpoint where the terraform provider will point to. Defaults to \"%s\"."
-	insecureDesc     = "Allow the provider to skip TLS validation on its outgoing HTTP calls."
-	timeoutDesc      = "Timeout used for individual HTTP calls. Defaults to \"1m\"."
-	verboseDesc      = "When set, a \"request.log\" file will be written with all outgoing HTTP requests. Defaults to \"false\"."
-	verboseCredsDesc = "When set with verbose, the contents of the Authorization header will not be redacted. Defaults to \"false\"."
+	endpointDesc        = "Endpoint where the terraform provider will point to. Defaults to \"%s\"."
+	insecureDesc        = "Allow the provider to skip TLS validation on its outgoing HTTP calls."
+	timeoutDesc         = "Timeout used for individual HTTP calls. Defaults to \"1m\"."
+	verboseDesc         = "When set, a \"request.log\" file will be written with all outgoing HTTP requests. Defaults to \"false\"."
+	verboseCredsDesc    = "When set with verbose, the contents of the Authorization header will not be redacted. Defaults to \"false\"."
+	retryMaxBackoffDesc = "Maximum backoff between two attempts of a retried Serverless API request. Retries also stop before the operation timeout is exceeded. Defaults to \"%s\"."
+	apiQPSDesc          = "Maximum number of Serverless API requests per second. It also sets the burst, the number of requests which may be sent at once: one second worth of requests, rounded up, e.g. 2.5 allows bursts of 3 requests. Defaults to \"0\", which disables the client-side rate limiting."
+	extraHeadersDesc    = "Additional HTTP headers which are set on every request to the Serverless API, e.g. when a corporate gateway requires custom headers. Headers set by the provider itself, such as Authorization, Content-Type or If-Match, can't be overridden."
+	debugLogFileDesc    = "When set, all Serverless API requests and responses are appended to this file, including their full bodies. Credentials are redacted."
+	allowedCIDRsDesc    = "When set, serverless traffic filter rules with an IP address or CIDR mask source are only allowed if the source is contained in one of these CIDR masks. Rules violating this policy are rejected when applying."
+	namePatternDesc     = "When set, the names of serverless traffic filters must match this regular expression, e.g. to enforce naming conventions. Names not matching it are rejected when planning."
+	mutationWindowDesc  = "When set, the serverless traffic filter, traffic filter association and default traffic filters resources are only created, updated and deleted during this daily time range in UTC, e.g. \"22:00-06:00\". Changes outside of it fail, reads are always allowed. Other resources aren't restricted."
+	defaultFilterDesc   = "Description of serverless traffic filters whose description attribute is unset, e.g. to have all traffic filters managed by Terraform carry a standard note."
+	diagnosticsJSONDesc = "When set, the diagnostics of the serverless traffic filter resources are additionally logged at the INFO level as single line JSON objects, e.g. for CI systems ingesting structured logs. Defaults to \"false\"."
+
+	unknownProviderValueDetail = "The value must be known when configuring the provider, it can't depend on values only known after apply."
 )
 
 var (
@@ -86,12 +120,25 @@ func ProviderWithClient(client *api.API, version string) provider.Provider {
 	return &Provider{client: client, version: version}
 }
 
+func ProviderWithClients(client *api.API, slsClient serverless.ClientWithResponsesInterface, version string) provider.Provider {
+	return &Provider{client: client, slsClient: slsClient, version: version}
+}
+
 var _ provider.Provider = (*Provider)(nil)
+var _ provider.ProviderWithFunctions = (*Provider)(nil)
+var _ provider.ProviderWithValidateConfig = (*Provider)(nil)
 
 type Provider struct {
-	version   string
-	client    *api.API
-	slsClient serverless.ClientWithResponsesInterface
+	version            string
+	client             *api.API
+	slsClient          serverless.ClientWithResponsesInterface
+	allowedSourceCIDRs []netip.Prefix
+	namePattern        *regexp.Regexp
+	defaultFilterDesc  string
+	mutationWindow     *util.MutationWindow
+	diagnosticsJSONLog bool
+	filterLists        *serverlessutil.FilterListCache
+	debugLogs          debugLogFiles
 }
 
 func (p *Provider) Metadata(ctx context.Context, request provider.MetadataRequest, response *provider.MetadataResponse) {
@@ -108,6 +155,14 @@ func (p *Provider) DataSources(ctx context.Context) []func() datasource.DataSour
 		privatelinkdatasource.GcpDataSource,
 		privatelinkdatasource.AzureDataSource,
 		func() datasource.DataSource { return &deploymenttemplates.DataSource{} },
+		serverlesstrafficfilterdatasource.NewDataSource,
+		serverlesstrafficfilterinventorydatasource.NewDataSource,
+		serverlesstrafficfiltersbyiddatasource.NewDataSource,
+		serverlessfilterprojectcompatibilitydatasource.NewDataSource,
+		serverlessprojectinfodatasource.NewDataSource,
+		serverlesstrafficfilterbynamedatasource.NewDataSource,
+		serverlessprojectsmissingfilterdatasource.NewDataSource,
+		serverlessprojecttrafficfiltersdatasource.NewDataSource,
 	}
 }
 
@@ -125,6 +180,17 @@ func (p *Provider) Resources(ctx context.Context) []func() resource.Resource {
 		func() resource.Resource { return &organizationresource.Resource{} },
 		serverlesstrafficfilterresource.NewResource,
 		serverlesstrafficfilterassocresource.NewResource,
+		serverlesstrafficfilterdefaultsresource.NewResource,
+	}
+}
+
+func (p *Provider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		associationidfunction.NewFunction,
+		filteriddifffunction.NewFunction,
+		mergerulesfunction.NewFunction,
+		rulestocsvfunction.NewFunction,
+		validatetrafficfilterfunction.NewFunction,
 	}
 }
 
@@ -172,28 +238,114 @@ func (p *Provider) Schema(_ context.Context, req provider.SchemaRequest, resp *p
 				Description: timeoutDesc,
 				Optional:    true,
 			},
+			"api_retry_max_backoff": schema.StringAttribute{
+				Description: fmt.Sprintf(retryMaxBackoffDesc, transport.DefaultRetryMaxBackoff),
+				Optional:    true,
+			},
+			"api_qps": schema.Float64Attribute{
+				Description: apiQPSDesc,
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"debug_log_file": schema.StringAttribute{
+				Description: debugLogFileDesc,
+				Optional:    true,
+			},
+			"extra_headers": schema.MapAttribute{
+				Description: extraHeadersDesc,
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(
+						stringvalidator.RegexMatches(transport.HeaderNameRegex, "must be a valid HTTP header name"),
+					),
+				},
+			},
+			"allowed_source_cidrs": schema.ListAttribute{
+				Description: allowedCIDRsDesc,
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"name_pattern": schema.StringAttribute{
+				Description: namePatternDesc,
+				Optional:    true,
+			},
+			"default_filter_description": schema.StringAttribute{
+				Description: defaultFilterDesc,
+				Optional:    true,
+			},
+			"allowed_mutation_window": schema.StringAttribute{
+				Description: mutationWindowDesc,
+				Optional:    true,
+			},
+			"diagnostics_json_log": schema.BoolAttribute{
+				Description: diagnosticsJSONDesc,
+				Optional:    true,
+			},
 		},
 	}
 }
 
+// ValidateConfig rejects extra headers overriding the ones set by the provider itself, which
+// would break the authentication or the concurrency checks of the Serverless API requests.
+func (p *Provider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var headers types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("extra_headers"), &headers)...)
+	if resp.Diagnostics.HasError() || headers.IsNull() || headers.IsUnknown() {
+		return
+	}
+
+	for name := range headers.Elements() {
+		if transport.IsReservedHeader(name) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("extra_headers").AtMapKey(name),
+				"Reserved extra header",
+				fmt.Sprintf("The %s header is set by the provider itself and can't be overridden.", name),
+			)
+		}
+	}
+}
+
 // Retrieve provider data from configuration
 type providerConfig struct {
-	Endpoint           types.String `tfsdk:"endpoint"`
-	ApiKey             types.String `tfsdk:"apikey"`
-	Username           types.String `tfsdk:"username"`
-	Password           types.String `tfsdk:"password"`
-	Insecure           types.Bool   `tfsdk:"insecure"`
-	Timeout            types.String `tfsdk:"timeout"`
-	Verbose            types.Bool   `tfsdk:"verbose"`
-	VerboseCredentials types.Bool   `tfsdk:"verbose_credentials"`
-	VerboseFile        types.String `tfsdk:"verbose_file"`
+	Endpoint           types.String  `tfsdk:"endpoint"`
+	ApiKey             types.String  `tfsdk:"apikey"`
+	Username           types.String  `tfsdk:"username"`
+	Password           types.String  `tfsdk:"password"`
+	Insecure           types.Bool    `tfsdk:"insecure"`
+	Timeout            types.String  `tfsdk:"timeout"`
+	Verbose            types.Bool    `tfsdk:"verbose"`
+	VerboseCredentials types.Bool    `tfsdk:"verbose_credentials"`
+	VerboseFile        types.String  `tfsdk:"verbose_file"`
+	RetryMaxBackoff    types.String  `tfsdk:"api_retry_max_backoff"`
+	APIQPS             types.Float64 `tfsdk:"api_qps"`
+	ExtraHeaders       types.Map     `tfsdk:"extra_headers"`
+	DebugLogFile       types.String  `tfsdk:"debug_log_file"`
+	AllowedSourceCIDRs types.List    `tfsdk:"allowed_source_cidrs"`
+	NamePattern        types.String  `tfsdk:"name_pattern"`
+	DefaultFilterDesc  types.String  `tfsdk:"default_filter_description"`
+	MutationWindow     types.String  `tfsdk:"allowed_mutation_window"`
+	DiagnosticsJSONLog types.Bool    `tfsdk:"diagnostics_json_log"`
 }
 
 func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	// Created once, so that listings are shared by everything resolving traffic filter names.
+	if p.filterLists == nil {
+		p.filterLists = serverlessutil.NewFilterListCache(serverlessutil.FilterListCacheTTL)
+	}
+
 	if p.client != nil {
 		data := internal.ProviderClients{
-			Stateful:   p.client,
-			Serverless: p.slsClient,
+			Stateful:           p.client,
+			Serverless:         p.slsClient,
+			AllowedSourceCIDRs: p.allowedSourceCIDRs,
+			NamePattern:        p.namePattern,
+			DefaultFilterDesc:  p.defaultFilterDesc,
+			MutationWindow:     p.mutationWindow,
+			DiagnosticsJSONLog: p.diagnosticsJSONLog,
+			FilterLists:        p.filterLists,
 		}
 		// Required for unit tests, because a mock client is pre-created there.
 		resp.DataSourceData = data
@@ -304,6 +456,70 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		verboseFile = util.MultiGetenvOrDefault([]string{"EC_VERBOSE_FILE"}, "request.log")
 	}
 
+	retryMaxBackoffStr := config.RetryMaxBackoff.ValueString()
+
+	if config.RetryMaxBackoff.ValueString() == "" {
+		retryMaxBackoffStr = util.MultiGetenvOrDefault([]string{"EC_API_RETRY_MAX_BACKOFF"}, transport.DefaultRetryMaxBackoff.String())
+	}
+
+	retryMaxBackoff, err := time.ParseDuration(retryMaxBackoffStr)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create client", fmt.Sprintf("Invalid value '%v' for api_retry_max_backoff: %s", retryMaxBackoffStr, err))
+		return
+	}
+
+	apiQPS := config.APIQPS.ValueFloat64()
+
+	if config.APIQPS.IsNull() {
+		apiQPSStr := util.MultiGetenvOrDefault([]string{"EC_API_QPS"}, "0")
+
+		if apiQPS, err = strconv.ParseFloat(apiQPSStr, 64); err != nil || apiQPS < 0 {
+			resp.Diagnostics.AddError(
+				"Unable to create client",
+				fmt.Sprintf("Invalid value '%v' in 'EC_API_QPS'", apiQPSStr),
+			)
+			return
+		}
+	}
+
+	debugLogFile := config.DebugLogFile.ValueString()
+
+	if config.DebugLogFile.IsNull() {
+		debugLogFile = util.MultiGetenvOrDefault([]string{"EC_DEBUG_LOG_FILE"}, "")
+	}
+
+	extraHeaders, diags := parseExtraHeaders(ctx, config.ExtraHeaders)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	allowedSourceCIDRs, diags := parseAllowedSourceCIDRs(ctx, config.AllowedSourceCIDRs)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	namePattern, diags := parseNamePattern(config.NamePattern)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	mutationWindow, diags := parseMutationWindow(config.MutationWindow)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	debugLog, err := p.debugLogs.open(debugLogFile)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create client", err.Error())
+		return
+	}
+
 	cfg, err := newAPIConfig(apiSetup{
 		endpoint:           endpoint,
 		apikey:             apiKey,
@@ -333,14 +549,15 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
-	serverlessClient, err := serverless.NewClientWithResponses(
-		cfg.Host,
-		serverless.WithHTTPClient(cfg.Client),
-		serverless.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
-			cfg.AuthWriter.AuthRequest(req)
-			return nil
-		}),
-	)
+	serverlessClient, err := newServerlessClient(cfg, serverlessSetup{
+		extraHeaders: extraHeaders,
+		retry: transport.RetryConfig{
+			MaxRetries: transport.DefaultMaxRetries,
+			MaxBackoff: retryMaxBackoff,
+		},
+		qps:      apiQPS,
+		debugLog: debugLog,
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create serverless Client",
@@ -350,14 +567,133 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 
 	p.client = client
 	p.slsClient = serverlessClient
+	p.allowedSourceCIDRs = allowedSourceCIDRs
+	p.namePattern = namePattern
+	p.defaultFilterDesc = config.DefaultFilterDesc.ValueString()
+	p.mutationWindow = mutationWindow
+	p.diagnosticsJSONLog = config.DiagnosticsJSONLog.ValueBool()
 	data := internal.ProviderClients{
-		Stateful:   client,
-		Serverless: serverlessClient,
+		Stateful:           client,
+		Serverless:         serverlessClient,
+		AllowedSourceCIDRs: allowedSourceCIDRs,
+		NamePattern:        namePattern,
+		DefaultFilterDesc:  p.defaultFilterDesc,
+		MutationWindow:     mutationWindow,
+		DiagnosticsJSONLog: p.diagnosticsJSONLog,
+		FilterLists:        p.filterLists,
 	}
 	resp.DataSourceData = data
 	resp.ResourceData = data
 }
 
+// parseExtraHeaders reads the headers of the extra_headers attribute, which must be known
+// when configuring the provider.
+func parseExtraHeaders(ctx context.Context, headers types.Map) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if headers.IsNull() {
+		return nil, diags
+	}
+	if headers.IsUnknown() {
+		diags.AddAttributeError(path.Root("extra_headers"), "Unknown extra headers", unknownProviderValueDetail)
+		return nil, diags
+	}
+	for name, value := range headers.Elements() {
+		if value.IsUnknown() {
+			diags.AddAttributeError(path.Root("extra_headers").AtMapKey(name), "Unknown extra header", unknownProviderValueDetail)
+		}
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	result := make(map[string]string, len(headers.Elements()))
+	diags.Append(headers.ElementsAs(ctx, &result, false)...)
+	return result, diags
+}
+
+// parseAllowedSourceCIDRs parses the CIDR masks of the allowed_source_cidrs attribute.
+// A nil result means that all sources are allowed. The CIDR masks must be known when
+// configuring the provider.
+func parseAllowedSourceCIDRs(ctx context.Context, list types.List) ([]netip.Prefix, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if list.IsNull() {
+		return nil, diags
+	}
+	if list.IsUnknown() {
+		diags.AddAttributeError(path.Root("allowed_source_cidrs"), "Unknown allowed source CIDRs", unknownProviderValueDetail)
+		return nil, diags
+	}
+	for i, cidr := range list.Elements() {
+		if cidr.IsUnknown() {
+			diags.AddAttributeError(path.Root("allowed_source_cidrs").AtListIndex(i), "Unknown allowed source CIDR", unknownProviderValueDetail)
+		}
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var cidrs []string
+	diags.Append(list.ElementsAs(ctx, &cidrs, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	result := make([]netip.Prefix, 0, len(cidrs))
+	for i, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("allowed_source_cidrs").AtListIndex(i),
+				"Invalid allowed source CIDR",
+				fmt.Sprintf("%q is not a valid CIDR mask: %s", cidr, err),
+			)
+			continue
+		}
+		result = append(result, prefix.Masked())
+	}
+	return result, diags
+}
+
+// parseNamePattern compiles the regular expression of the name_pattern attribute.
+// A nil result means that any name is allowed.
+func parseNamePattern(pattern types.String) (*regexp.Regexp, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if pattern.IsNull() {
+		return nil, diags
+	}
+
+	result, err := regexp.Compile(pattern.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("name_pattern"),
+			"Invalid name pattern",
+			fmt.Sprintf("%q is not a valid regular expression: %s", pattern.ValueString(), err),
+		)
+		return nil, diags
+	}
+	return result, diags
+}
+
+// parseMutationWindow parses the time range of the allowed_mutation_window attribute.
+// A nil result means that changes are always allowed.
+func parseMutationWindow(window types.String) (*util.MutationWindow, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if window.IsNull() {
+		return nil, diags
+	}
+
+	result, err := util.ParseMutationWindow(window.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("allowed_mutation_window"),
+			"Invalid allowed mutation window",
+			err.Error(),
+		)
+		return nil, diags
+	}
+	return result, diags
+}
+
 func validateEndpoint(ctx context.Context, endpoint string) diag.Diagnostics {
 	validateReq := validator.StringRequest{
 		Path:        path.Root("endpoint"),
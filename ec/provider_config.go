@@ -18,13 +18,18 @@
 package ec
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/elastic/cloud-sdk-go/pkg/api"
 	"github.com/elastic/cloud-sdk-go/pkg/auth"
+	"github.com/elastic/terraform-provider-ec/ec/internal/gen/serverless"
+	"github.com/elastic/terraform-provider-ec/ec/internal/transport"
 )
 
 const (
@@ -83,6 +88,41 @@ func newAPIConfig(setup apiSetup) (api.Config, error) {
 	}, nil
 }
 
+type serverlessSetup struct {
+	extraHeaders map[string]string
+	retry        transport.RetryConfig
+	qps          float64
+	debugLog     io.Writer
+}
+
+// newServerlessClient creates the serverless API client. It shares the
+// transport of the given config, which has to be set up by api.NewAPI first.
+func newServerlessClient(cfg api.Config, setup serverlessSetup) (serverless.ClientWithResponsesInterface, error) {
+	rt := transport.NewBodyLogTransport(cfg.Client.Transport, setup.debugLog)
+	rt = transport.NewTrafficFiltersTransport(rt)
+	rt = transport.NewRateLimitTransport(rt, setup.qps, transport.BurstForQPS(setup.qps))
+	// Below the retries, so that retried 503s count towards opening the circuit.
+	rt = transport.NewCircuitBreakerTransport(rt, transport.DefaultCircuitBreakerThreshold, transport.DefaultCircuitBreakerCooldown)
+	rt = transport.NewRetryTransport(rt, setup.retry)
+	rt = transport.NewDeprecationTransport(rt)
+	rt = transport.NewFailureTransport(rt)
+	rt = transport.NewHeaderTransport(rt, setup.extraHeaders)
+
+	httpClient := &http.Client{
+		Transport: rt,
+		Timeout:   cfg.Client.Timeout,
+	}
+
+	return serverless.NewClientWithResponses(
+		cfg.Host,
+		serverless.WithHTTPClient(httpClient),
+		serverless.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			cfg.AuthWriter.AuthRequest(req)
+			return nil
+		}),
+	)
+}
+
 func verboseSettings(name string, verbose, redactAuth bool) (api.VerboseSettings, error) {
 	var cfg api.VerboseSettings
 	if !verbose {
@@ -101,6 +141,39 @@ func verboseSettings(name string, verbose, redactAuth bool) (api.VerboseSettings
 	}, nil
 }
 
+// debugLogFiles keeps the debug log files open across configurations of the
+// provider, so that configuring it again doesn't open another handle to the
+// same file. The files are closed when the provider process exits.
+type debugLogFiles struct {
+	mu    sync.Mutex
+	files map[string]*os.File
+}
+
+// open opens the file the serverless API requests are logged to, failing
+// early if it isn't writable. No file is opened for an empty name.
+func (d *debugLogFiles) open(name string) (io.Writer, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if f, ok := d.files[name]; ok {
+		return f, nil
+	}
+
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf(`failed opening debug log file "%s": %w`, name, err)
+	}
+	if d.files == nil {
+		d.files = map[string]*os.File{}
+	}
+	d.files[name] = f
+	return f, nil
+}
+
 func userAgent(v string) string {
 	return fmt.Sprintf(providerUserAgentFmt, v, api.DefaultUserAgent)
 }
@@ -18,9 +18,11 @@
 package ec
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"syscall"
@@ -341,3 +343,52 @@ func Test_newAPIConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_newServerlessClient(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cfg, err := newAPIConfig(apiSetup{
+		endpoint: server.URL,
+		apikey:   "secret",
+		timeout:  defaultTimeout,
+	})
+	assert.NoError(t, err)
+
+	client, err := newServerlessClient(cfg, serverlessSetup{
+		extraHeaders: map[string]string{"X-Request-Source": "terraform"},
+	})
+	assert.NoError(t, err)
+
+	_, err = client.GetTrafficFilterWithResponse(context.Background(), "filter-id")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "terraform", received.Get("X-Request-Source"))
+	assert.Equal(t, "ApiKey secret", received.Get("Authorization"))
+}
+
+func Test_debugLogFiles(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "debug.log")
+
+	var logs debugLogFiles
+	t.Cleanup(func() {
+		for _, f := range logs.files {
+			f.Close()
+		}
+	})
+
+	first, err := logs.open(name)
+	assert.NoError(t, err)
+	second, err := logs.open(name)
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Len(t, logs.files, 1)
+
+	none, err := logs.open("")
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+}
@@ -21,7 +21,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/elastic/terraform-provider-ec/ec/internal"
 	"github.com/elastic/terraform-provider-ec/ec/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -126,6 +128,152 @@ func Test_Configure(t *testing.T) {
 			}(),
 		},
 
+		{
+			name: `provider config doesn't define "api_qps" and "EC_API_QPS" contains invalid value`,
+			args: args{
+				env: map[string]string{
+					"EC_API_QPS": "-1",
+				},
+				config: providerConfig{
+					Endpoint: types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:   types.StringValue("secret"),
+					APIQPS:   types.Float64Null(),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddError("Unable to create client", "Invalid value '-1' in 'EC_API_QPS'")
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines a "debug_log_file" which isn't writable`,
+			args: args{
+				config: providerConfig{
+					Endpoint:     types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:       types.StringValue("secret"),
+					DebugLogFile: types.StringValue("/nonexistent/debug.log"),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddError("Unable to create client", `failed opening debug log file "/nonexistent/debug.log": open /nonexistent/debug.log: no such file or directory`)
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines unknown "extra_headers"`,
+			args: args{
+				config: providerConfig{
+					Endpoint:     types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:       types.StringValue("secret"),
+					ExtraHeaders: types.MapUnknown(types.StringType),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddAttributeError(path.Root("extra_headers"), "Unknown extra headers", unknownProviderValueDetail)
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines an unknown "extra_headers" entry`,
+			args: args{
+				config: providerConfig{
+					Endpoint: types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:   types.StringValue("secret"),
+					ExtraHeaders: types.MapValueMust(types.StringType, map[string]attr.Value{
+						"X-Team":    types.StringValue("network"),
+						"X-Gateway": types.StringUnknown(),
+					}),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddAttributeError(path.Root("extra_headers").AtMapKey("X-Gateway"), "Unknown extra header", unknownProviderValueDetail)
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines unknown "allowed_source_cidrs"`,
+			args: args{
+				config: providerConfig{
+					Endpoint:           types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:             types.StringValue("secret"),
+					AllowedSourceCIDRs: types.ListUnknown(types.StringType),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddAttributeError(path.Root("allowed_source_cidrs"), "Unknown allowed source CIDRs", unknownProviderValueDetail)
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines an invalid "allowed_source_cidrs" entry`,
+			args: args{
+				config: providerConfig{
+					Endpoint:           types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:             types.StringValue("secret"),
+					AllowedSourceCIDRs: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("10.0.0.0/8"), types.StringValue("10.0.0.1")}),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddAttributeError(
+					path.Root("allowed_source_cidrs").AtListIndex(1),
+					"Invalid allowed source CIDR",
+					`"10.0.0.1" is not a valid CIDR mask: netip.ParsePrefix("10.0.0.1"): no '/'`,
+				)
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines an invalid "allowed_mutation_window"`,
+			args: args{
+				config: providerConfig{
+					Endpoint:       types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:         types.StringValue("secret"),
+					MutationWindow: types.StringValue("22:00"),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddAttributeError(
+					path.Root("allowed_mutation_window"),
+					"Invalid allowed mutation window",
+					`expected a time range such as 22:00-06:00, got "22:00"`,
+				)
+				return diags
+			}(),
+		},
+
+		{
+			name: `provider config defines an invalid "name_pattern"`,
+			args: args{
+				config: providerConfig{
+					Endpoint:    types.StringValue("https://cloud.elastic.co/api"),
+					ApiKey:      types.StringValue("secret"),
+					NamePattern: types.StringValue("prod-("),
+				},
+			},
+			diags: func() diag.Diagnostics {
+				var diags diag.Diagnostics
+				diags.AddAttributeError(
+					path.Root("name_pattern"),
+					"Invalid name pattern",
+					`"prod-(" is not a valid regular expression: error parsing regexp: missing closing ): `+"`prod-(`",
+				)
+				return diags
+			}(),
+		},
+
 		{
 			name: `provider config is read from environment variables`,
 			args: args{
@@ -168,7 +316,7 @@ func Test_Configure(t *testing.T) {
 
 			var config types.Object
 
-			diags := tfsdk.ValueFrom(context.Background(), &tt.args.config, schemaResp.Schema.Type(), &config)
+			diags := tfsdk.ValueFrom(context.Background(), withNullCollections(tt.args.config), schemaResp.Schema.Type(), &config)
 
 			assert.Nil(t, diags)
 
@@ -192,3 +340,91 @@ func Test_Configure(t *testing.T) {
 		})
 	}
 }
+
+// withNullCollections sets the collections left unset by a test to null, as their zero value
+// has no element type.
+func withNullCollections(config providerConfig) *providerConfig {
+	if config.ExtraHeaders.ElementType(context.Background()) == nil {
+		config.ExtraHeaders = types.MapNull(types.StringType)
+	}
+	if config.AllowedSourceCIDRs.ElementType(context.Background()) == nil {
+		config.AllowedSourceCIDRs = types.ListNull(types.StringType)
+	}
+	return &config
+}
+
+func Test_Configure_SharesServerlessClient(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	schemaResp := provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	assert.Nil(t, schemaResp.Diagnostics)
+
+	util.GetEnv = func(string) string { return "" }
+
+	var config types.Object
+	assert.Nil(t, tfsdk.ValueFrom(ctx, withNullCollections(providerConfig{
+		Endpoint: types.StringValue("https://cloud.elastic.co/api"),
+		ApiKey:   types.StringValue("secret"),
+	}), schemaResp.Schema.Type(), &config))
+	rawConfig, err := config.ToTerraformValue(ctx)
+	assert.Nil(t, err)
+	req := provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: rawConfig}}
+
+	resp := provider.ConfigureResponse{}
+	p.Configure(ctx, req, &resp)
+	assert.Nil(t, resp.Diagnostics)
+
+	// Two resources and a data source configured from the same provider data.
+	first, diags := internal.ConvertProviderData(resp.ResourceData)
+	assert.Nil(t, diags)
+	second, diags := internal.ConvertProviderData(resp.ResourceData)
+	assert.Nil(t, diags)
+	dataSource, diags := internal.ConvertProviderData(resp.DataSourceData)
+	assert.Nil(t, diags)
+
+	assert.NotNil(t, first.Serverless)
+	assert.Same(t, first.Serverless, second.Serverless)
+	assert.Same(t, first.Serverless, dataSource.Serverless)
+	assert.NotNil(t, first.FilterLists)
+	assert.Same(t, first.FilterLists, dataSource.FilterLists)
+
+	// Configuring the provider again keeps the client.
+	again := provider.ConfigureResponse{}
+	p.Configure(ctx, req, &again)
+	reconfigured, diags := internal.ConvertProviderData(again.ResourceData)
+	assert.Nil(t, diags)
+	assert.Same(t, first.Serverless, reconfigured.Serverless)
+	assert.Same(t, first.FilterLists, reconfigured.FilterLists)
+}
+
+func Test_ValidateConfig_ReservedExtraHeaders(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	schemaResp := provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	assert.Nil(t, schemaResp.Diagnostics)
+
+	var config types.Object
+	assert.Nil(t, tfsdk.ValueFrom(ctx, withNullCollections(providerConfig{
+		ExtraHeaders: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"authorization": types.StringValue("Bearer other"),
+			"X-Team":        types.StringValue("platform"),
+		}),
+	}), schemaResp.Schema.Type(), &config))
+	rawConfig, err := config.ToTerraformValue(ctx)
+	assert.Nil(t, err)
+
+	resp := provider.ValidateConfigResponse{}
+	p.ValidateConfig(ctx, provider.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: rawConfig}}, &resp)
+
+	var expected diag.Diagnostics
+	expected.AddAttributeError(
+		path.Root("extra_headers").AtMapKey("authorization"),
+		"Reserved extra header",
+		"The authorization header is set by the provider itself and can't be overridden.",
+	)
+	assert.Equal(t, expected, resp.Diagnostics)
+}